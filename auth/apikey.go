@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DefaultAPIKeyHeader is name of header that carries API key, unless configured otherwise
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// APIKeyStore resolves API key to subject it belongs to.
+type APIKeyStore interface {
+	Lookup(key string) (subject string, ok bool)
+}
+
+// APIKeys is APIKeyStore that holds keys in memory.
+// Keys are stored as SHA-256 digests, so that lookup time does not depend on content of key.
+type APIKeys map[[sha256.Size]byte]string
+
+func (a APIKeys) Lookup(key string) (string, bool) {
+	s, ok := a[sha256.Sum256([]byte(key))]
+	return s, ok
+}
+
+// Add registers key that belongs to given subject.
+func (a APIKeys) Add(subject, key string) {
+	a[sha256.Sum256([]byte(key))] = subject
+}
+
+// StaticAPIKeys creates APIKeys from map of subject to key.
+func StaticAPIKeys(keys map[string]string) APIKeys {
+	out := APIKeys{}
+	for subject, key := range keys {
+		out.Add(subject, key)
+	}
+	return out
+}
+
+// ParseAPIKeys parses content with one "subject:key" entry per line.
+// Empty lines and lines starting with '#' are ignored.
+func ParseAPIKeys(content string) (APIKeys, error) {
+	out := APIKeys{}
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		subject, key, ok := strings.Cut(text, ":")
+		if !ok || len(subject) == 0 || len(key) == 0 {
+			return nil, fmt.Errorf("api keys: malformed entry at line %d", line)
+		}
+		out.Add(subject, key)
+	}
+	return out, sc.Err()
+}
+
+// LoadAPIKeys loads API keys file from given path, see ParseAPIKeys for format.
+func LoadAPIKeys(path string) (APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAPIKeys(string(data))
+}
+
+type apiKeyAuthenticator struct {
+	header string
+	query  string
+	store  APIKeyStore
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	var key string
+	if len(a.header) > 0 {
+		key = r.Header.Get(a.header)
+	}
+	if len(key) == 0 && len(a.query) > 0 {
+		key = r.URL.Query().Get(a.query)
+	}
+	if len(key) == 0 {
+		return nil, ErrNoCredentials
+	}
+	subject, ok := a.store.Lookup(key)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Subject: subject, Scheme: "APIKey"}, nil
+}
+
+func (a *apiKeyAuthenticator) Challenge(error) string {
+	if len(a.header) > 0 {
+		return fmt.Sprintf(`APIKey header=%q`, a.header)
+	}
+	return fmt.Sprintf(`APIKey query=%q`, a.query)
+}
+
+// NewAPIKeyAuthenticator creates Authenticator that takes API key from given header
+// or, if header is not present, from given query parameter.
+// Either of header or query can be empty to disable that source.
+// Note that keys sent in query tend to end up in access logs.
+func NewAPIKeyAuthenticator(header, query string, store APIKeyStore) Authenticator {
+	return &apiKeyAuthenticator{header: header, query: query, store: store}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth provides authentication middleware with pluggable authenticators.
+// Authenticated Principal is stored in request context, see FromContext.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+var (
+	// ErrNoCredentials is returned by Authenticator when request carries no credentials for its scheme.
+	// Next Authenticator in chain is consulted in such case.
+	ErrNoCredentials = errors.New("no credentials provided")
+	// ErrInvalidCredentials is returned by Authenticator when credentials are present, but they are not valid.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is authenticated identity that made the request.
+type Principal struct {
+	// Subject identifies principal, such as username or name of API key
+	Subject string
+	// Scheme is name of authentication scheme that authenticated principal, such as "Basic"
+	Scheme string
+	// Claims holds additional attributes provided by authenticator, such as claims of token
+	Claims Claims
+}
+
+type ctxKey struct{}
+
+// NewContext returns copy of parent context that carries provided principal.
+func NewContext(parent context.Context, p *Principal) context.Context {
+	return context.WithValue(parent, ctxKey{}, p)
+}
+
+// FromContext returns principal carried by ctx, or nil if request is not authenticated.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(ctxKey{}).(*Principal)
+	return p
+}
+
+// Authenticator authenticates request using single scheme.
+type Authenticator interface {
+	// Authenticate returns principal that made the request.
+	// ErrNoCredentials must be returned (possibly wrapped) if request carries no credentials for this scheme.
+	Authenticate(r *http.Request) (*Principal, error)
+	// Challenge returns value of WWW-Authenticate header that is sent when authentication fails.
+	// err is error returned from Authenticate, or ErrNoCredentials if no authenticator accepted credentials.
+	// Empty string means that no challenge is sent for this scheme.
+	Challenge(err error) string
+}
+
+// Builder is interface to support building of authentication middleware.
+type Builder interface {
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) Builder
+
+	// Optional lets requests without any credentials through without principal.
+	// Requests with invalid credentials are still rejected.
+	Optional() Builder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type builderImpl struct {
+	authenticators []Authenticator
+	out            output.Interface
+	optional       bool
+}
+
+func (b *builderImpl) WithOutput(out output.Interface) Builder {
+	b.out = out
+	return b
+}
+
+func (b *builderImpl) Optional() Builder {
+	b.optional = true
+	return b
+}
+
+func (b *builderImpl) Build() func(http.Handler) http.Handler {
+	var (
+		out      = b.out
+		optional = b.optional
+		authn    = make([]Authenticator, len(b.authenticators))
+	)
+	copy(authn, b.authenticators)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				failed    Authenticator
+				failedErr = ErrNoCredentials
+			)
+			for _, a := range authn {
+				p, err := a.Authenticate(r)
+				if err == nil {
+					next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+					return
+				}
+				if !errors.Is(err, ErrNoCredentials) {
+					failed, failedErr = a, err
+					break
+				}
+			}
+			if failed == nil && optional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if failed != nil {
+				// client picked the scheme, challenge it only
+				if c := failed.Challenge(failedErr); len(c) > 0 {
+					w.Header().Add("WWW-Authenticate", c)
+				}
+			} else {
+				for _, a := range authn {
+					if c := a.Challenge(failedErr); len(c) > 0 {
+						w.Header().Add("WWW-Authenticate", c)
+					}
+				}
+			}
+			out.SendWithStatus(w, output.WrapError(failedErr, http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	}
+}
+
+// NewBuilder creates Builder that authenticates requests using provided authenticators.
+// Authenticators are consulted in order, first one that accepts credentials wins.
+// Once authenticator rejects credentials, request is rejected without consulting remaining ones.
+func NewBuilder(authenticators ...Authenticator) Builder {
+	return &builderImpl{
+		authenticators: authenticators,
+		out:            output.DefaultOutput(),
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func serve(t *testing.T, mw func(http.Handler) http.Handler, req *http.Request) (*httptest.ResponseRecorder, *Principal) {
+	t.Helper()
+	var p *Principal
+	rr := httptest.NewRecorder()
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p = FromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(rr, req)
+	return rr, p
+}
+
+func testHtpasswd(t *testing.T) Htpasswd {
+	bc, err := bcrypt.GenerateFromPassword([]byte("secret1"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	sum := sha1.Sum([]byte("secret2"))
+	h, err := ParseHtpasswd(fmt.Sprintf(`
+# comment
+alice:%s
+bob:{SHA}%s
+`, bc, base64.StdEncoding.EncodeToString(sum[:])))
+	assert.NoError(t, err)
+	return h
+}
+
+func TestHtpasswd(t *testing.T) {
+	h := testHtpasswd(t)
+	assert.True(t, h.Verify("alice", "secret1"))
+	assert.False(t, h.Verify("alice", "secret2"))
+	assert.True(t, h.Verify("bob", "secret2"))
+	assert.False(t, h.Verify("bob", "secret1"))
+	assert.False(t, h.Verify("eve", "secret1"))
+	assert.Equal(t, bcrypt.MinCost, h.bcryptCost())
+	assert.Equal(t, bcrypt.DefaultCost, Htpasswd{}.bcryptCost())
+
+	_, err := ParseHtpasswd("nohash")
+	assert.Error(t, err)
+	for _, hash := range []string{"plain", "$apr1$salt$hash", "$5$salt$hash", "$6$salt$hash", "{SHA256}abcd"} {
+		_, err = ParseHtpasswd("carol:" + hash)
+		assert.ErrorContains(t, err, "unsupported hash format")
+	}
+	assert.False(t, Htpasswd{"dave": "plain"}.Verify("dave", "plain"))
+
+	f := filepath.Join(t.TempDir(), ".htpasswd")
+	assert.NoError(t, os.WriteFile(f, []byte("bob:{SHA}"+h["bob"][5:]+"\n"), 0o600))
+	h, err = LoadHtpasswd(f)
+	assert.NoError(t, err)
+	assert.True(t, h.Verify("bob", "secret2"))
+}
+
+func TestBasicAuth(t *testing.T) {
+	mw := NewBuilder(NewBasicAuthenticator("test", testHtpasswd(t))).Build()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr, _ := serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, `Basic realm="test", charset="UTF-8"`, rr.Header().Get("WWW-Authenticate"))
+
+	req.SetBasicAuth("alice", "wrong")
+	rr, _ = serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), ErrInvalidCredentials.Error())
+
+	req.SetBasicAuth("alice", "secret1")
+	rr, p := serve(t, mw, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "alice", p.Subject)
+	assert.Equal(t, "Basic", p.Scheme)
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	keys, err := ParseAPIKeys("svc1:key1\n\n# comment\nsvc2:key2")
+	assert.NoError(t, err)
+	mw := NewBuilder(NewAPIKeyAuthenticator(DefaultAPIKeyHeader, "api_key", keys)).Build()
+
+	req := httptest.NewRequest(http.MethodGet, "/?api_key=key2", nil)
+	rr, p := serve(t, mw, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "svc2", p.Subject)
+
+	req.Header.Set(DefaultAPIKeyHeader, "key1")
+	rr, p = serve(t, mw, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "svc1", p.Subject)
+	assert.Equal(t, "APIKey", p.Scheme)
+
+	req.Header.Set(DefaultAPIKeyHeader, "key3")
+	rr, _ = serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, `APIKey header="X-API-Key"`, rr.Header().Get("WWW-Authenticate"))
+
+	_, ok := StaticAPIKeys(map[string]string{"svc": "k"}).Lookup("k")
+	assert.True(t, ok)
+	_, err = ParseAPIKeys("svc1")
+	assert.Error(t, err)
+}
+
+func TestBearerAuth(t *testing.T) {
+	verifier := func(_ context.Context, token string) (*Principal, error) {
+		switch token {
+		case "good":
+			return &Principal{Subject: "user1", Claims: map[string]interface{}{"scope": "read"}}, nil
+		case "unknown":
+			return nil, nil
+		}
+		return nil, ErrInvalidCredentials
+	}
+	mw := NewBuilder(
+		NewBasicAuthenticator("test", Htpasswd{}),
+		NewBearerAuthenticator("test", verifier),
+	).Build()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr, _ := serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, []string{
+		`Basic realm="test", charset="UTF-8"`,
+		`Bearer realm="test"`,
+	}, rr.Header().Values("WWW-Authenticate"))
+
+	req.Header.Set("Authorization", "Bearer bad")
+	rr, _ = serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, []string{`Bearer realm="test", error="invalid_token"`}, rr.Header().Values("WWW-Authenticate"))
+
+	req.Header.Set("Authorization", "Bearer unknown")
+	rr, _ = serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req.Header.Set("Authorization", "bearer good")
+	rr, p := serve(t, mw, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "user1", p.Subject)
+	assert.Equal(t, "Bearer", p.Scheme)
+	assert.Equal(t, "read", p.Claims["scope"])
+}
+
+func TestOptional(t *testing.T) {
+	mw := NewBuilder(NewAPIKeyAuthenticator(DefaultAPIKeyHeader, "", APIKeys{})).Optional().Build()
+
+	rr, p := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Nil(t, p)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultAPIKeyHeader, "unknown")
+	rr, _ = serve(t, mw, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
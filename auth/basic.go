@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CredentialStore verifies username and password.
+type CredentialStore interface {
+	Verify(user, password string) bool
+}
+
+// Htpasswd is CredentialStore backed by htpasswd-style content.
+// Supported hash formats are bcrypt ($2a$, $2b$, $2y$) and {SHA} (base64 of SHA-1).
+type Htpasswd map[string]string
+
+// dummyHashes holds bcrypt hash per cost, which is verified when user is unknown,
+// so that response time does not reveal existence of user
+var dummyHashes sync.Map
+
+func dummyHash(cost int) []byte {
+	if h, ok := dummyHashes.Load(cost); ok {
+		return h.([]byte)
+	}
+	h, _ := bcrypt.GenerateFromPassword([]byte("dummy"), cost)
+	dummyHashes.Store(cost, h)
+	return h
+}
+
+// bcryptCost returns cost of bcrypt entries, assuming that all of them use the same one
+func (h Htpasswd) bcryptCost() int {
+	for _, hash := range h {
+		if c, err := bcrypt.Cost([]byte(hash)); err == nil {
+			return c
+		}
+	}
+	return bcrypt.DefaultCost
+}
+
+func (h Htpasswd) Verify(user, password string) bool {
+	hash, ok := h[user]
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(h.bcryptCost()), []byte(password))
+		return false
+	}
+	return verifyHash(hash, password)
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func verifyHash(hash, password string) bool {
+	eq := func(expected, actual string) bool {
+		return subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+	}
+	switch {
+	case isBcrypt(hash):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		return eq(hash[5:], base64.StdEncoding.EncodeToString(sum[:]))
+	default:
+		// rejected by ParseHtpasswd, but Htpasswd can be constructed directly
+		return false
+	}
+}
+
+// ParseHtpasswd parses htpasswd content, one "user:hash" entry per line.
+// Empty lines and lines starting with '#' are ignored.
+// Entries with unsupported hash format (such as $apr1$, $5$, $6$ or plain text) are rejected with error.
+func ParseHtpasswd(content string) (Htpasswd, error) {
+	out := Htpasswd{}
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(text, ":")
+		if !ok || len(user) == 0 || len(hash) == 0 {
+			return nil, fmt.Errorf("htpasswd: malformed entry at line %d", line)
+		}
+		if !isBcrypt(hash) && !strings.HasPrefix(hash, "{SHA}") {
+			return nil, fmt.Errorf("htpasswd: unsupported hash format of user %q at line %d", user, line)
+		}
+		out[user] = hash
+	}
+	return out, sc.Err()
+}
+
+// LoadHtpasswd loads htpasswd file from given path.
+func LoadHtpasswd(path string) (Htpasswd, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHtpasswd(string(data))
+}
+
+type basicAuthenticator struct {
+	realm string
+	store CredentialStore
+}
+
+func (b *basicAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if len(r.Header.Get("Authorization")) == 0 {
+		return nil, ErrNoCredentials
+	}
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		// other scheme, such as Bearer
+		return nil, ErrNoCredentials
+	}
+	if !b.store.Verify(user, pass) {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Subject: user, Scheme: "Basic"}, nil
+}
+
+func (b *basicAuthenticator) Challenge(error) string {
+	return fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, b.realm)
+}
+
+// NewBasicAuthenticator creates Authenticator for HTTP Basic scheme (RFC 7617).
+func NewBasicAuthenticator(realm string, store CredentialStore) Authenticator {
+	return &basicAuthenticator{realm: realm, store: store}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TokenVerifier verifies bearer token and returns principal it was issued to.
+// Error should wrap ErrInvalidCredentials when token is not valid, nil principal is treated the same way.
+type TokenVerifier func(ctx context.Context, token string) (*Principal, error)
+
+type bearerAuthenticator struct {
+	realm    string
+	verifier TokenVerifier
+}
+
+// bearerToken extracts token from Authorization header, or returns empty string if there is none
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func (b *bearerAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	if len(token) == 0 {
+		return nil, ErrNoCredentials
+	}
+	p, err := b.verifier(r.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		// verifier that does not know token
+		return nil, ErrInvalidCredentials
+	}
+	if len(p.Scheme) == 0 {
+		p.Scheme = "Bearer"
+	}
+	return p, nil
+}
+
+func (b *bearerAuthenticator) Challenge(err error) string {
+	// RFC 6750, section 3
+	if errors.Is(err, ErrNoCredentials) {
+		return fmt.Sprintf(`Bearer realm=%q`, b.realm)
+	}
+	return fmt.Sprintf(`Bearer realm=%q, error="invalid_token"`, b.realm)
+}
+
+// NewBearerAuthenticator creates Authenticator for Bearer scheme (RFC 6750), using provided TokenVerifier.
+func NewBearerAuthenticator(realm string, verifier TokenVerifier) Authenticator {
+	return &bearerAuthenticator{realm: realm, verifier: verifier}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Claims are attributes of principal, such as claims of JWT.
+// Values have types produced by encoding/json, i.e. numbers are float64 and arrays are []interface{}.
+type Claims map[string]interface{}
+
+// ClaimsFromContext returns claims of principal carried by ctx, or nil if request is not authenticated.
+func ClaimsFromContext(ctx context.Context) Claims {
+	if p := FromContext(ctx); p != nil {
+		return p.Claims
+	}
+	return nil
+}
+
+// String returns value of claim if it is string, or empty string otherwise.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns value of claim that is either single string or array of strings.
+// Non-string array elements are skipped.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// Time returns value of claim that holds NumericDate (RFC 7519, section 2).
+func (c Claims) Time(name string) (time.Time, bool) {
+	var f float64
+	switch v := c[name].(type) {
+	case float64:
+		f = v
+	case int64:
+		f = float64(v)
+	case int:
+		f = float64(v)
+	default:
+		return time.Time{}, false
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)), true
+}
+
+// Scopes returns scopes granted to principal.
+// Both space-delimited "scope" claim (RFC 8693) and "scp" array are recognized.
+func (c Claims) Scopes() []string {
+	if s, ok := c["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	return c.Strings("scp")
+}
+
+// Has returns true if claim contains given value, either as single string or as element of array.
+func (c Claims) Has(name, value string) bool {
+	return slices.Contains(c.Strings(name), value)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/x509"
+	"net/http"
+)
+
+type clientCertAuthenticator struct {
+	subjectFn func(cert *x509.Certificate) string
+}
+
+// DefaultCertSubject returns common name of certificate's subject or, if it is empty,
+// first of URI, DNS or e-mail SANs. Distinguished name is used as last resort.
+func DefaultCertSubject(cert *x509.Certificate) string {
+	switch {
+	case len(cert.Subject.CommonName) > 0:
+		return cert.Subject.CommonName
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	default:
+		return cert.Subject.String()
+	}
+}
+
+func certClaims(cert *x509.Certificate) Claims {
+	uris := make([]string, 0, len(cert.URIs))
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	return Claims{
+		"subject": cert.Subject.String(),
+		"cn":      cert.Subject.CommonName,
+		"o":       cert.Subject.Organization,
+		"ou":      cert.Subject.OrganizationalUnit,
+		"issuer":  cert.Issuer.String(),
+		"serial":  cert.SerialNumber.String(),
+		"dns":     cert.DNSNames,
+		"email":   cert.EmailAddresses,
+		"uri":     uris,
+		"ip":      ips,
+	}
+}
+
+func (c *clientCertAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	// certificates that were merely requested, but not verified, don't prove anything
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, ErrNoCredentials
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	return &Principal{Subject: c.subjectFn(cert), Scheme: "ClientCert", Claims: certClaims(cert)}, nil
+}
+
+func (c *clientCertAuthenticator) Challenge(error) string {
+	// there is no HTTP challenge for TLS client authentication
+	return ""
+}
+
+// NewClientCertAuthenticator creates Authenticator that uses client certificate verified during TLS handshake,
+// see config.TLSConfig. Certificate's subject, issuer, serial number and SANs are available as claims
+// "subject", "cn", "o", "ou", "issuer", "serial", "dns", "email", "uri" and "ip".
+// subjectFn maps certificate to Principal.Subject, nil means DefaultCertSubject.
+func NewClientCertAuthenticator(subjectFn func(cert *x509.Certificate) string) Authenticator {
+	if subjectFn == nil {
+		subjectFn = DefaultCertSubject
+	}
+	return &clientCertAuthenticator{subjectFn: subjectFn}
+}
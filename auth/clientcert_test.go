@@ -0,0 +1,117 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCert(t *testing.T, tmpl *x509.Certificate, parent *x509.Certificate, signer *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, signer = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+func TestClientCertAuth(t *testing.T) {
+	ca, caKey := newCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	spiffe, _ := url.Parse("spiffe://example.org/svc")
+	client, clientKey := newCert(t, &x509.Certificate{
+		Subject:     pkix.Name{OrganizationalUnit: []string{"ops"}},
+		URIs:        []*url.URL{spiffe},
+		DNSNames:    []string{"svc.example.org"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600))
+
+	var p *Principal
+	srv := httptest.NewUnstartedServer(NewBuilder(NewClientCertAuthenticator(nil)).Build()(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p = FromContext(r.Context())
+			w.WriteHeader(http.StatusNoContent)
+		})))
+	srv.TLS = &tls.Config{}
+	assert.NoError(t, (&config.TLSConfig{
+		ClientAuth:   config.TLSConfigClientAuthVerifyIfGiven,
+		ClientCAFile: caFile,
+	}).Apply(srv.TLS))
+	srv.StartTLS()
+	defer srv.Close()
+
+	// no certificate
+	resp, err := srv.Client().Get(srv.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	c := srv.Client()
+	// don't reuse connection established without certificate
+	c.CloseIdleConnections()
+	c.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{{
+		Certificate: [][]byte{client.Raw},
+		PrivateKey:  clientKey,
+	}}
+	resp, err = c.Get(srv.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "spiffe://example.org/svc", p.Subject)
+	assert.Equal(t, "ClientCert", p.Scheme)
+	assert.True(t, p.Claims.Has("ou", "ops"))
+	assert.Equal(t, []string{"svc.example.org"}, p.Claims.Strings("dns"))
+	assert.Equal(t, "CN=Test CA", p.Claims.String("issuer"))
+}
+
+func TestClientCertUnverified(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+	_, err := NewClientCertAuthenticator(nil).Authenticate(req)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+}
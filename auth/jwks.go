@@ -0,0 +1,230 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultJWKSCheckInterval is minimal interval between checks whether JWKS file has changed
+const DefaultJWKSCheckInterval = 5 * time.Second
+
+// JWK is single key of JSON Web Key Set (RFC 7517).
+type JWK struct {
+	// KeyID is value of "kid" parameter, matched against "kid" header of token
+	KeyID string
+	// Algorithm is value of "alg" parameter. When not empty, key is only used with this algorithm
+	Algorithm string
+	// Key is one of *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte (HMAC secret)
+	Key interface{}
+}
+
+// KeySource provides keys used to verify signature of tokens.
+type KeySource interface {
+	Keys() []*JWK
+}
+
+// JWKS is static KeySource.
+type JWKS []*JWK
+
+func (s JWKS) Keys() []*JWK {
+	return s
+}
+
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+func b64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func (r *rawJWK) parse() (*JWK, error) {
+	k := &JWK{KeyID: r.Kid, Algorithm: r.Alg}
+	switch r.Kty {
+	case "RSA":
+		n, err := b64(r.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.New("invalid modulus")
+		}
+		e, err := b64(r.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid exponent")
+		}
+		k.Key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "EC":
+		var curve elliptic.Curve
+		switch r.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", r.Crv)
+		}
+		x, err1 := b64(r.X)
+		y, err2 := b64(r.Y)
+		size := (curve.Params().BitSize + 7) / 8
+		if err1 != nil || err2 != nil || len(x) != size || len(y) != size {
+			return nil, errors.New("invalid coordinates")
+		}
+		pub, err := ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, err
+		}
+		k.Key = pub
+	case "OKP":
+		if r.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", r.Crv)
+		}
+		x, err := b64(r.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key")
+		}
+		k.Key = ed25519.PublicKey(x)
+	case "oct":
+		secret, err := b64(r.K)
+		if err != nil || len(secret) == 0 {
+			return nil, errors.New("invalid secret")
+		}
+		k.Key = secret
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", r.Kty)
+	}
+	return k, nil
+}
+
+// ParseJWKS parses JSON Web Key Set.
+// Keys that are designated for encryption ("use":"enc") are skipped.
+func ParseJWKS(data []byte) (JWKS, error) {
+	var set struct {
+		Keys []rawJWK `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	out := make(JWKS, 0, len(set.Keys))
+	for i := range set.Keys {
+		if set.Keys[i].Use == "enc" {
+			continue
+		}
+		k, err := set.Keys[i].parse()
+		if err != nil {
+			return nil, fmt.Errorf("jwks: key #%d (kid=%q): %w", i, set.Keys[i].Kid, err)
+		}
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+// LoadJWKS loads JSON Web Key Set from given path.
+func LoadJWKS(path string) (JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// JWKSFile is KeySource backed by file, which is reloaded when it changes.
+// Change is detected using modification time and size of file, at most once per check interval.
+// When reload fails, previously loaded keys are kept.
+type JWKSFile struct {
+	path     string
+	interval time.Duration
+	l        *slog.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	keys    JWKS
+	checked time.Time
+	modTime time.Time
+	size    int64
+}
+
+// NewJWKSFile loads JSON Web Key Set from given path. Zero checkInterval means DefaultJWKSCheckInterval.
+func NewJWKSFile(path string, checkInterval time.Duration) (*JWKSFile, error) {
+	if checkInterval <= 0 {
+		checkInterval = DefaultJWKSCheckInterval
+	}
+	f := &JWKSFile{path: path, interval: checkInterval, l: slog.Default(), now: time.Now}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = f.load(fi); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// load must be called with lock held
+func (f *JWKSFile) load(fi os.FileInfo) error {
+	keys, err := LoadJWKS(f.path)
+	if err != nil {
+		return err
+	}
+	f.keys, f.modTime, f.size, f.checked = keys, fi.ModTime(), fi.Size(), f.now()
+	return nil
+}
+
+func (f *JWKSFile) Keys() []*JWK {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	if now.Sub(f.checked) < f.interval {
+		return f.keys
+	}
+	f.checked = now
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		f.l.Warn("unable to check JWKS file", "path", f.path, "error", err)
+		return f.keys
+	}
+	if fi.ModTime().Equal(f.modTime) && fi.Size() == f.size {
+		return f.keys
+	}
+	if err = f.load(fi); err != nil {
+		f.l.Warn("unable to reload JWKS file, keeping previous keys", "path", f.path, "error", err)
+		return f.keys
+	}
+	f.l.Info("JWKS file reloaded", "path", f.path, "keys", len(f.keys))
+	return f.keys
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultJWTLeeway is tolerated clock skew between issuer of token and this server
+const DefaultJWTLeeway = 30 * time.Second
+
+var (
+	ErrTokenMalformed   = fmt.Errorf("%w: malformed token", ErrInvalidCredentials)
+	ErrTokenAlgorithm   = fmt.Errorf("%w: unsupported token algorithm", ErrInvalidCredentials)
+	ErrTokenSignature   = fmt.Errorf("%w: invalid token signature", ErrInvalidCredentials)
+	ErrTokenExpired     = fmt.Errorf("%w: token is expired", ErrInvalidCredentials)
+	ErrTokenNotYetValid = fmt.Errorf("%w: token is not valid yet", ErrInvalidCredentials)
+	ErrTokenIssuer      = fmt.Errorf("%w: token issuer is not trusted", ErrInvalidCredentials)
+	ErrTokenAudience    = fmt.Errorf("%w: token is not intended for this audience", ErrInvalidCredentials)
+)
+
+type jwtAlg struct {
+	hash crypto.Hash
+	// verify returns true if signature of digest (or message for EdDSA) is valid for given key,
+	// or false when key is of wrong type
+	verify func(key interface{}, hash crypto.Hash, msg, digest, sig []byte) bool
+}
+
+func verifyHMAC(key interface{}, hash crypto.Hash, msg, _, sig []byte) bool {
+	secret, ok := key.([]byte)
+	if !ok {
+		return false
+	}
+	mac := hmac.New(hash.New, secret)
+	mac.Write(msg)
+	return hmac.Equal(mac.Sum(nil), sig)
+}
+
+func verifyRSA(key interface{}, hash crypto.Hash, _, digest, sig []byte) bool {
+	pub, ok := key.(*rsa.PublicKey)
+	return ok && rsa.VerifyPKCS1v15(pub, hash, digest, sig) == nil
+}
+
+func verifyRSAPSS(key interface{}, hash crypto.Hash, _, digest, sig []byte) bool {
+	pub, ok := key.(*rsa.PublicKey)
+	return ok && rsa.VerifyPSS(pub, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+}
+
+func verifyECDSA(curveBits int) func(interface{}, crypto.Hash, []byte, []byte, []byte) bool {
+	return func(key interface{}, _ crypto.Hash, _, digest, sig []byte) bool {
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve.Params().BitSize != curveBits {
+			return false
+		}
+		// JWS uses fixed-size R || S rather than ASN.1
+		size := (curveBits + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+}
+
+func verifyEdDSA(key interface{}, _ crypto.Hash, msg, _, sig []byte) bool {
+	pub, ok := key.(ed25519.PublicKey)
+	return ok && ed25519.Verify(pub, msg, sig)
+}
+
+// supported JWS algorithms (RFC 7518, RFC 8037). "none" is deliberately absent.
+var jwtAlgs = map[string]jwtAlg{
+	"HS256": {crypto.SHA256, verifyHMAC},
+	"HS384": {crypto.SHA384, verifyHMAC},
+	"HS512": {crypto.SHA512, verifyHMAC},
+	"RS256": {crypto.SHA256, verifyRSA},
+	"RS384": {crypto.SHA384, verifyRSA},
+	"RS512": {crypto.SHA512, verifyRSA},
+	"PS256": {crypto.SHA256, verifyRSAPSS},
+	"PS384": {crypto.SHA384, verifyRSAPSS},
+	"PS512": {crypto.SHA512, verifyRSAPSS},
+	"ES256": {crypto.SHA256, verifyECDSA(256)},
+	"ES384": {crypto.SHA384, verifyECDSA(384)},
+	"ES512": {crypto.SHA512, verifyECDSA(521)},
+	"EdDSA": {0, verifyEdDSA},
+}
+
+// JWTVerifierBuilder is interface to support building of TokenVerifier that validates JSON Web Tokens (RFC 7519).
+// Only compact JWS serialization is supported, encrypted tokens (JWE) are not.
+type JWTVerifierBuilder interface {
+	// WithIssuer sets accepted values of "iss" claim. By default, issuer is not checked.
+	WithIssuer(iss ...string) JWTVerifierBuilder
+
+	// WithAudience sets values of "aud" claim, at least one of which must be present in token.
+	// By default, audience is not checked.
+	WithAudience(aud ...string) JWTVerifierBuilder
+
+	// WithAlgorithms restricts accepted signature algorithms. By default, all supported algorithms are accepted,
+	// each of them only with matching type of key.
+	WithAlgorithms(algs ...string) JWTVerifierBuilder
+
+	// WithLeeway sets tolerated clock skew used when checking "exp", "nbf" and "iat" claims.
+	// By default, DefaultJWTLeeway is used.
+	WithLeeway(d time.Duration) JWTVerifierBuilder
+
+	// WithSubjectClaim sets name of claim used as Principal.Subject. By default, "sub" is used.
+	WithSubjectClaim(name string) JWTVerifierBuilder
+
+	// Build creates TokenVerifier with all configured aspects.
+	Build() TokenVerifier
+}
+
+type jwtVerifierBuilderImpl struct {
+	keys    KeySource
+	iss     []string
+	aud     []string
+	algs    []string
+	leeway  time.Duration
+	subject string
+	now     func() time.Time
+}
+
+func (j *jwtVerifierBuilderImpl) WithIssuer(iss ...string) JWTVerifierBuilder {
+	j.iss = iss
+	return j
+}
+
+func (j *jwtVerifierBuilderImpl) WithAudience(aud ...string) JWTVerifierBuilder {
+	j.aud = aud
+	return j
+}
+
+func (j *jwtVerifierBuilderImpl) WithAlgorithms(algs ...string) JWTVerifierBuilder {
+	j.algs = algs
+	return j
+}
+
+func (j *jwtVerifierBuilderImpl) WithLeeway(d time.Duration) JWTVerifierBuilder {
+	j.leeway = d
+	return j
+}
+
+func (j *jwtVerifierBuilderImpl) WithSubjectClaim(name string) JWTVerifierBuilder {
+	j.subject = name
+	return j
+}
+
+func (j *jwtVerifierBuilderImpl) Build() TokenVerifier {
+	c := *j
+	return func(_ context.Context, token string) (*Principal, error) {
+		claims, err := c.verify(token)
+		if err != nil {
+			return nil, err
+		}
+		return &Principal{Subject: claims.String(c.subject), Scheme: "Bearer", Claims: claims}, nil
+	}
+}
+
+func (j *jwtVerifierBuilderImpl) verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+	var hdr struct {
+		Alg  string   `json:"alg"`
+		Kid  string   `json:"kid"`
+		Crit []string `json:"crit"`
+	}
+	hdrJSON, err := b64(parts[0])
+	if err != nil || json.Unmarshal(hdrJSON, &hdr) != nil {
+		return nil, ErrTokenMalformed
+	}
+	sig, err := b64(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	// no extensions are understood, so token that requires any must be rejected (RFC 7515, section 4.1.11)
+	if len(hdr.Crit) > 0 {
+		return nil, ErrTokenMalformed
+	}
+	alg, ok := jwtAlgs[hdr.Alg]
+	if !ok || (len(j.algs) > 0 && !slices.Contains(j.algs, hdr.Alg)) {
+		return nil, ErrTokenAlgorithm
+	}
+	if err = j.verifySignature(hdr.Alg, hdr.Kid, alg, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	payload, err := b64(parts[1])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil || claims == nil {
+		return nil, ErrTokenMalformed
+	}
+	return claims, j.validate(claims)
+}
+
+func (j *jwtVerifierBuilderImpl) verifySignature(name, kid string, alg jwtAlg, msg, sig []byte) error {
+	var digest []byte
+	if alg.hash != 0 {
+		h := alg.hash.New()
+		h.Write(msg)
+		digest = h.Sum(nil)
+	}
+	for _, k := range j.keys.Keys() {
+		if len(kid) > 0 && k.KeyID != kid {
+			continue
+		}
+		if len(k.Algorithm) > 0 && k.Algorithm != name {
+			continue
+		}
+		if alg.verify(k.Key, alg.hash, msg, digest, sig) {
+			return nil
+		}
+	}
+	return ErrTokenSignature
+}
+
+func (j *jwtVerifierBuilderImpl) validate(claims Claims) error {
+	now := j.now()
+	exp, ok := claims.Time("exp")
+	if !ok || !now.Before(exp.Add(j.leeway)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := claims.Time("nbf"); ok && now.Add(j.leeway).Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+	if iat, ok := claims.Time("iat"); ok && now.Add(j.leeway).Before(iat) {
+		return ErrTokenNotYetValid
+	}
+	if len(j.iss) > 0 && !slices.Contains(j.iss, claims.String("iss")) {
+		return ErrTokenIssuer
+	}
+	if len(j.aud) > 0 && !slices.ContainsFunc(j.aud, func(aud string) bool {
+		return claims.Has("aud", aud)
+	}) {
+		return ErrTokenAudience
+	}
+	return nil
+}
+
+// NewJWTVerifierBuilder creates JWTVerifierBuilder that verifies signatures using keys from provided KeySource,
+// such as JWKS or JWKSFile. Tokens must carry "exp" claim.
+func NewJWTVerifierBuilder(keys KeySource) JWTVerifierBuilder {
+	return &jwtVerifierBuilderImpl{
+		keys:    keys,
+		leeway:  DefaultJWTLeeway,
+		subject: "sub",
+		now:     time.Now,
+	}
+}
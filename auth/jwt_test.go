@@ -0,0 +1,226 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var enc = base64.RawURLEncoding.EncodeToString
+
+type testKeys struct {
+	rsa    *rsa.PrivateKey
+	ec     *ecdsa.PrivateKey
+	ed     ed25519.PrivateKey
+	secret []byte
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	var (
+		k   = &testKeys{secret: []byte("0123456789abcdef0123456789abcdef")}
+		err error
+	)
+	k.rsa, err = rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	k.ec, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, k.ed, err = ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	return k
+}
+
+func (k *testKeys) jwks(t *testing.T) []byte {
+	ecPub, err := k.ec.PublicKey.Bytes()
+	assert.NoError(t, err)
+	data, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "n": enc(k.rsa.N.Bytes()), "e": enc(big.NewInt(int64(k.rsa.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": enc(ecPub[1:33]), "y": enc(ecPub[33:])},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": enc(k.ed.Public().(ed25519.PublicKey))},
+		{"kty": "oct", "kid": "hs", "alg": "HS256", "k": enc(k.secret)},
+		{"kty": "RSA", "use": "enc", "n": "invalid"},
+	}})
+	assert.NoError(t, err)
+	return data
+}
+
+func (k *testKeys) sign(t *testing.T, alg, kid string, claims Claims) string {
+	hdr, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	msg := enc(hdr) + "." + enc(payload)
+	digest := sha256.Sum256([]byte(msg))
+	var (
+		sig []byte
+		err error
+	)
+	switch alg {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case "EdDSA":
+		sig = ed25519.Sign(k.ed, []byte(msg))
+	case "HS256":
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write([]byte(msg))
+		sig = mac.Sum(nil)
+	}
+	assert.NoError(t, err)
+	return msg + "." + enc(sig)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	keys := newTestKeys(t)
+	jwks, err := ParseJWKS(keys.jwks(t))
+	assert.NoError(t, err)
+	assert.Len(t, jwks, 4)
+
+	now := time.Unix(1_800_000_000, 0)
+	b := NewJWTVerifierBuilder(jwks).
+		WithIssuer("https://issuer.local").
+		WithAudience("api").(*jwtVerifierBuilderImpl)
+	b.now = func() time.Time { return now }
+	v := b.Build()
+	claims := func(mod func(c Claims)) Claims {
+		c := Claims{
+			"sub": "alice",
+			"iss": "https://issuer.local",
+			"aud": []string{"other", "api"},
+			"exp": now.Add(time.Minute).Unix(),
+			"iat": now.Unix(),
+		}
+		if mod != nil {
+			mod(c)
+		}
+		return c
+	}
+
+	for _, alg := range []string{"RS256", "ES256", "EdDSA", "HS256"} {
+		t.Run(alg, func(t *testing.T) {
+			p, err := v(context.Background(), keys.sign(t, alg, "", claims(nil)))
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", p.Subject)
+			assert.Equal(t, "Bearer", p.Scheme)
+			assert.Equal(t, []string{"other", "api"}, p.Claims.Strings("aud"))
+		})
+	}
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"malformed", "abc.def", ErrTokenMalformed},
+		{"none", enc([]byte(`{"alg":"none"}`)) + "." + enc([]byte(`{}`)) + ".", ErrTokenAlgorithm},
+		{"kid mismatch", keys.sign(t, "RS256", "ec", claims(nil)), ErrTokenSignature},
+		{"tampered", func() string {
+			parts := strings.Split(keys.sign(t, "ES256", "ec", claims(nil)), ".")
+			parts[1] = enc([]byte(`{"sub":"admin"}`))
+			return strings.Join(parts, ".")
+		}(), ErrTokenSignature},
+		{"expired", keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+			c["exp"] = now.Add(-DefaultJWTLeeway).Unix()
+		})), ErrTokenExpired},
+		{"no exp", keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+			delete(c, "exp")
+		})), ErrTokenExpired},
+		{"nbf", keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+			c["nbf"] = now.Add(time.Hour).Unix()
+		})), ErrTokenNotYetValid},
+		{"iat", keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+			c["iat"] = now.Add(time.Hour).Unix()
+		})), ErrTokenNotYetValid},
+		{"issuer", keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+			c["iss"] = "https://evil.local"
+		})), ErrTokenIssuer},
+		{"audience", keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+			c["aud"] = "other"
+		})), ErrTokenAudience},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := v(context.Background(), tc.token)
+			assert.ErrorIs(t, err, tc.err)
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	// within leeway
+	_, err = v(context.Background(), keys.sign(t, "HS256", "hs", claims(func(c Claims) {
+		c["exp"] = now.Add(-time.Second).Unix()
+		c["nbf"] = now.Add(time.Second).Unix()
+	})))
+	assert.NoError(t, err)
+
+	// restricted algorithms
+	b.WithAlgorithms("RS256")
+	_, err = b.Build()(context.Background(), keys.sign(t, "HS256", "hs", claims(nil)))
+	assert.ErrorIs(t, err, ErrTokenAlgorithm)
+}
+
+func TestParseJWKS(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AAAA","y":"AAAA"}]}`))
+	assert.Error(t, err)
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"XYZ"}]}`))
+	assert.Error(t, err)
+	_, err = ParseJWKS([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestJWKSFile(t *testing.T) {
+	keys := newTestKeys(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"keys":[]}`), 0o600))
+
+	f, err := NewJWKSFile(path, time.Minute)
+	assert.NoError(t, err)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+	assert.Empty(t, f.Keys())
+
+	assert.NoError(t, os.WriteFile(path, keys.jwks(t), 0o600))
+	// not checked until interval elapses
+	assert.Empty(t, f.Keys())
+	now = now.Add(time.Minute)
+	assert.Len(t, f.Keys(), 4)
+
+	// broken file keeps previous keys
+	assert.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	now = now.Add(time.Minute)
+	assert.Len(t, f.Keys(), 4)
+
+	_, err = NewJWKSFile(filepath.Join(t.TempDir(), "missing.json"), 0)
+	assert.Error(t, err)
+}
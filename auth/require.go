@@ -0,0 +1,149 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+var (
+	// ErrInsufficientScope is sent when principal lacks some of required scopes.
+	ErrInsufficientScope = errors.New("insufficient scope")
+	// ErrForbidden is sent when principal lacks required role or fails custom check.
+	ErrForbidden = errors.New("access denied")
+)
+
+// DefaultRolesClaim is name of claim that holds roles of principal, unless configured otherwise
+const DefaultRolesClaim = "roles"
+
+// RequirementBuilder is interface to support building of middleware that authorizes authenticated principal.
+// It must be placed after authentication middleware in chain, typically on individual routes.
+// Requests without principal are rejected with 401, requests that don't meet requirements with 403.
+type RequirementBuilder interface {
+	// WithScopes requires principal to be granted all of given scopes, see Claims.Scopes.
+	WithScopes(scopes ...string) RequirementBuilder
+
+	// WithRoles requires principal to have at least one of given roles.
+	WithRoles(roles ...string) RequirementBuilder
+
+	// WithRolesClaim sets name of claim that holds roles. By default, DefaultRolesClaim is used.
+	WithRolesClaim(name string) RequirementBuilder
+
+	// WithCheck adds custom check of principal's claims.
+	WithCheck(fn func(c Claims) bool) RequirementBuilder
+
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) RequirementBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type requirementBuilderImpl struct {
+	scopes     []string
+	roles      []string
+	rolesClaim string
+	checks     []func(c Claims) bool
+	out        output.Interface
+}
+
+func (b *requirementBuilderImpl) WithScopes(scopes ...string) RequirementBuilder {
+	b.scopes = append(b.scopes, scopes...)
+	return b
+}
+
+func (b *requirementBuilderImpl) WithRoles(roles ...string) RequirementBuilder {
+	b.roles = append(b.roles, roles...)
+	return b
+}
+
+func (b *requirementBuilderImpl) WithRolesClaim(name string) RequirementBuilder {
+	b.rolesClaim = name
+	return b
+}
+
+func (b *requirementBuilderImpl) WithCheck(fn func(c Claims) bool) RequirementBuilder {
+	b.checks = append(b.checks, fn)
+	return b
+}
+
+func (b *requirementBuilderImpl) WithOutput(out output.Interface) RequirementBuilder {
+	b.out = out
+	return b
+}
+
+func (b *requirementBuilderImpl) authorize(p *Principal) error {
+	granted := p.Claims.Scopes()
+	for _, s := range b.scopes {
+		if !slices.Contains(granted, s) {
+			return ErrInsufficientScope
+		}
+	}
+	if len(b.roles) > 0 {
+		if !slices.ContainsFunc(b.roles, func(r string) bool {
+			return p.Claims.Has(b.rolesClaim, r)
+		}) {
+			return ErrForbidden
+		}
+	}
+	for _, check := range b.checks {
+		if !check(p.Claims) {
+			return ErrForbidden
+		}
+	}
+	return nil
+}
+
+func (b *requirementBuilderImpl) Build() func(http.Handler) http.Handler {
+	c := *b
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := FromContext(r.Context())
+			if p == nil {
+				c.out.SendWithStatus(w, output.WrapError(ErrNoCredentials, http.StatusUnauthorized),
+					http.StatusUnauthorized)
+				return
+			}
+			err := c.authorize(p)
+			if err == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if errors.Is(err, ErrInsufficientScope) && p.Scheme == "Bearer" {
+				// RFC 6750, section 3.1
+				w.Header().Set("WWW-Authenticate",
+					fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, strings.Join(c.scopes, " ")))
+			}
+			c.out.SendWithStatus(w, output.WrapError(err, http.StatusForbidden), http.StatusForbidden)
+		})
+	}
+}
+
+// NewRequirementBuilder creates RequirementBuilder with defaults.
+// Without any requirement configured, middleware only ensures that request is authenticated.
+func NewRequirementBuilder() RequirementBuilder {
+	return &requirementBuilderImpl{
+		rolesClaim: DefaultRolesClaim,
+		out:        output.DefaultOutput(),
+	}
+}
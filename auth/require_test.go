@@ -0,0 +1,74 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirement(t *testing.T) {
+	serveAs := func(mw func(http.Handler) http.Handler, p *Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if p != nil {
+			req = req.WithContext(NewContext(req.Context(), p))
+		}
+		rr, _ := serve(t, mw, req)
+		return rr
+	}
+	p := &Principal{Subject: "alice", Scheme: "Bearer", Claims: Claims{
+		"scope": "read write",
+		"roles": []interface{}{"user", "auditor"},
+	}}
+
+	rr := serveAs(NewRequirementBuilder().Build(), nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serveAs(NewRequirementBuilder().WithScopes("read", "write").WithRoles("admin", "auditor").Build(), p)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = serveAs(NewRequirementBuilder().WithScopes("read", "delete").Build(), p)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, `Bearer error="insufficient_scope", scope="read delete"`, rr.Header().Get("WWW-Authenticate"))
+
+	rr = serveAs(NewRequirementBuilder().WithRoles("admin").Build(), p)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Empty(t, rr.Header().Get("WWW-Authenticate"))
+
+	rr = serveAs(NewRequirementBuilder().WithRolesClaim("groups").WithRoles("user").Build(), p)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = serveAs(NewRequirementBuilder().WithCheck(func(c Claims) bool {
+		return c.Has("roles", "user")
+	}).Build(), p)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestClaims(t *testing.T) {
+	c := Claims{"scp": []interface{}{"a", 1, "b"}, "exp": 1.5e9, "name": "x"}
+	assert.Equal(t, []string{"a", "b"}, c.Scopes())
+	assert.Equal(t, "x", c.String("name"))
+	assert.Equal(t, "", c.String("exp"))
+	exp, ok := c.Time("exp")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1.5e9), exp.Unix())
+	_, ok = c.Time("name")
+	assert.False(t, ok)
+}
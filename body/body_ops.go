@@ -18,33 +18,135 @@ package body
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+
+	"github.com/rkosegi/go-http-commons/output"
 )
 
-// ConsumeAsWithDecoder consumes arbitrary request body as a given type with provided decoder function
-func ConsumeAsWithDecoder[T any](req *http.Request, decFn func(io.Reader, *T) error) (*T, error) {
+var ErrTrailingData = errors.New("request body contains data after the first value")
+
+// DecoderOptions controls strictness of Decoder
+type DecoderOptions struct {
+	// DisallowUnknownFields causes decoding to fail when body contains fields that don't match target type
+	DisallowUnknownFields bool
+	// RejectTrailingData causes decoding to fail when there is any data after the first value
+	RejectTrailingData bool
+	// UseNumber causes numbers to be decoded into interface{} as json.Number instead of float64
+	UseNumber bool
+}
+
+type options struct {
+	DecoderOptions
+	maxBytes int64
+}
+
+// Option customizes how request body is consumed
+type Option func(*options)
+
+// WithMaxBytes limits size of request body to n bytes.
+// Body that exceeds this limit produces error that is sent with status 413 by output.Interface.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithDisallowUnknownFields causes decoding to fail when body contains fields that don't match target type.
+func WithDisallowUnknownFields() Option {
+	return func(o *options) {
+		o.DisallowUnknownFields = true
+	}
+}
+
+// WithRejectTrailingData causes decoding to fail when there is any data after the first value.
+func WithRejectTrailingData() Option {
+	return func(o *options) {
+		o.RejectTrailingData = true
+	}
+}
+
+// WithUseNumber causes JSON numbers to be decoded into interface{} as json.Number instead of float64.
+func WithUseNumber() Option {
+	return func(o *options) {
+		o.UseNumber = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// mapErr makes sure that body size violation is recognized by output.Interface
+func mapErr(err error) error {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return output.WrapError(err, http.StatusRequestEntityTooLarge)
+	}
+	return err
+}
+
+// DecodeJSON is Decoder for JSON documents
+func DecodeJSON(r io.Reader, v interface{}, o DecoderOptions) error {
+	dec := json.NewDecoder(r)
+	if o.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if o.UseNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if o.RejectTrailingData {
+		if _, err := dec.Token(); err != io.EOF {
+			return errors.Join(ErrTrailingData, err)
+		}
+	}
+	return nil
+}
+
+// ConsumeAsWithDecoder consumes arbitrary request body as a given type with provided decoder function.
+// Only WithMaxBytes option applies here, other options are handled by Decoder.
+func ConsumeAsWithDecoder[T any](req *http.Request, decFn func(io.Reader, *T) error, opts ...Option) (*T, error) {
 	var (
 		res T
 		err error
+		o             = newOptions(opts)
+		r   io.Reader = req.Body
 	)
-
-	if err = decFn(req.Body, &res); err != nil {
-		return nil, err
+	if o.maxBytes > 0 {
+		r = http.MaxBytesReader(nil, req.Body, o.maxBytes)
+	}
+	if err = decFn(r, &res); err != nil {
+		return nil, mapErr(err)
 	}
 	return &res, nil
 }
 
-// ConsumeAs consumes JSON request body as a given type
-func ConsumeAs[T any](req *http.Request) (*T, error) {
+// ConsumeAs consumes request body as a given type.
+// Decoder is selected based on Content-Type header of request, see RegisterDecoder.
+// If request has no Content-Type, body is assumed to be JSON.
+func ConsumeAs[T any](req *http.Request, opts ...Option) (*T, error) {
+	dec, err := decoderFor(req)
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
 	return ConsumeAsWithDecoder[T](req, func(r io.Reader, t *T) error {
-		return json.NewDecoder(r).Decode(t)
-	})
+		return dec(r, t, o.DecoderOptions)
+	}, opts...)
 }
 
-// PatchEntity merges existing entity with one from request body
-func PatchEntity[T any, K comparable](req *http.Request, getKeyFn func(*T) K, existingSupplierFn func(K) (*T, error), mergeFn func(b1, b2 *T) (*T, error)) (*T, error) {
-	fromReq, err := ConsumeAs[T](req)
+// PatchEntity merges existing entity with one from request body.
+// To apply JSON Merge Patch or JSON Patch documents instead, see PatchEntityByKey.
+func PatchEntity[T any, K comparable](req *http.Request, getKeyFn func(*T) K, existingSupplierFn func(K) (*T, error), mergeFn func(b1, b2 *T) (*T, error), opts ...Option) (*T, error) {
+	fromReq, err := ConsumeAs[T](req, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -19,9 +19,11 @@ package body
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"testing"
 
+	"github.com/rkosegi/go-http-commons/output"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,3 +57,98 @@ func TestPatchEntity(t *testing.T) {
 	assert.Equal(t, 100, out.Salary)
 	assert.Equal(t, "Bob", out.Name)
 }
+
+func TestConsumeAsOptions(t *testing.T) {
+	type Item struct {
+		Name  string
+		Value interface{}
+	}
+	newReq := func(body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "/item", bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		return req
+	}
+
+	t.Run("max bytes", func(t *testing.T) {
+		_, err := ConsumeAs[Item](newReq(`{"Name":"a very long name"}`), WithMaxBytes(10))
+		var se *output.StatusError
+		assert.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, se.Status)
+	})
+	t.Run("unknown fields", func(t *testing.T) {
+		_, err := ConsumeAs[Item](newReq(`{"Name":"x","Other":1}`))
+		assert.NoError(t, err)
+		_, err = ConsumeAs[Item](newReq(`{"Name":"x","Other":1}`), WithDisallowUnknownFields())
+		assert.Error(t, err)
+	})
+	t.Run("trailing data", func(t *testing.T) {
+		_, err := ConsumeAs[Item](newReq(`{"Name":"x"} garbage`))
+		assert.NoError(t, err)
+		_, err = ConsumeAs[Item](newReq(`{"Name":"x"} {}`), WithRejectTrailingData())
+		assert.ErrorIs(t, err, ErrTrailingData)
+		_, err = ConsumeAs[Item](newReq("{\"Name\":\"x\"}\n"), WithRejectTrailingData())
+		assert.NoError(t, err)
+	})
+	t.Run("use number", func(t *testing.T) {
+		out, err := ConsumeAs[Item](newReq(`{"Value":12345678901234567890}`), WithUseNumber())
+		assert.NoError(t, err)
+		assert.Equal(t, json.Number("12345678901234567890"), out.Value)
+	})
+}
+
+func TestConsumeAsContentType(t *testing.T) {
+	type Item struct {
+		Name  string   `json:"name" yaml:"name" xml:"name" form:"name"`
+		Count int      `json:"count" yaml:"count" xml:"count"`
+		Tags  []string `json:"tags" yaml:"tags" xml:"tags"`
+	}
+	newReq := func(ct, body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "/item", bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		if len(ct) > 0 {
+			req.Header.Set("Content-Type", ct)
+		}
+		return req
+	}
+	exp := &Item{Name: "x", Count: 2, Tags: []string{"a", "b"}}
+	for _, tc := range []struct {
+		ct   string
+		body string
+	}{
+		{"", `{"name":"x","count":2,"tags":["a","b"]}`},
+		{"application/json; charset=utf-8", `{"name":"x","count":2,"tags":["a","b"]}`},
+		{"application/vnd.acme.item+json", `{"name":"x","count":2,"tags":["a","b"]}`},
+		{"application/yaml", "name: x\ncount: 2\ntags: [a, b]\n"},
+		{"application/xml", `<Item><name>x</name><count>2</count><tags>a</tags><tags>b</tags></Item>`},
+		{"application/x-www-form-urlencoded", `name=x&count=2&tags=a&tags=b`},
+	} {
+		out, err := ConsumeAs[Item](newReq(tc.ct, tc.body))
+		assert.NoError(t, err, tc.ct)
+		assert.Equal(t, exp, out, tc.ct)
+	}
+
+	t.Run("yaml max bytes", func(t *testing.T) {
+		_, err := ConsumeAs[Item](newReq("application/yaml", "name: a very long name\n"), WithMaxBytes(10))
+		var se *output.StatusError
+		assert.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, se.Status)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ConsumeAs[Item](newReq("text/csv", "name,count"))
+		var umt *UnsupportedMediaTypeError
+		assert.ErrorAs(t, err, &umt)
+		assert.Equal(t, "text/csv", umt.MediaType)
+		var se *output.StatusError
+		assert.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusUnsupportedMediaType, se.Status)
+	})
+	t.Run("custom decoder", func(t *testing.T) {
+		RegisterDecoder("text/csv", func(r io.Reader, v interface{}, _ DecoderOptions) error {
+			v.(*Item).Name = "csv"
+			return nil
+		})
+		out, err := ConsumeAs[Item](newReq("text/csv", "name,count"))
+		assert.NoError(t, err)
+		assert.Equal(t, "csv", out.Name)
+	})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package body
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/rkosegi/go-http-commons/output"
+	"gopkg.in/yaml.v3"
+)
+
+// Decoder decodes content of reader into v
+type Decoder func(r io.Reader, v interface{}, o DecoderOptions) error
+
+// UnsupportedMediaTypeError is returned when there is no Decoder registered for Content-Type of request.
+// It is wrapped in output.StatusError, so it is sent with status 415 by output.Interface.
+type UnsupportedMediaTypeError struct {
+	MediaType string
+}
+
+func (e *UnsupportedMediaTypeError) Error() string {
+	return "unsupported media type: " + e.MediaType
+}
+
+var (
+	decodersLock sync.RWMutex
+	decoders     = map[string]Decoder{
+		"application/json":                  DecodeJSON,
+		"application/yaml":                  DecodeYAML,
+		"application/x-yaml":                DecodeYAML,
+		"text/yaml":                         DecodeYAML,
+		"application/xml":                   DecodeXML,
+		"text/xml":                          DecodeXML,
+		"application/x-www-form-urlencoded": DecodeForm,
+	}
+	// suffixDecoders handle structured syntax suffixes (RFC 6839), such as application/vnd.acme+json
+	suffixDecoders = map[string]Decoder{
+		"+json": DecodeJSON,
+		"+yaml": DecodeYAML,
+		"+xml":  DecodeXML,
+	}
+)
+
+// RegisterDecoder registers Decoder for given media type, replacing any existing one.
+// Media type must not contain parameters.
+func RegisterDecoder(mediaType string, dec Decoder) {
+	decodersLock.Lock()
+	defer decodersLock.Unlock()
+	decoders[strings.ToLower(mediaType)] = dec
+}
+
+func decoderFor(req *http.Request) (Decoder, error) {
+	ct := req.Header.Get("Content-Type")
+	if len(ct) == 0 {
+		return DecodeJSON, nil
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, output.WrapError(&UnsupportedMediaTypeError{MediaType: ct}, http.StatusUnsupportedMediaType)
+	}
+	decodersLock.RLock()
+	defer decodersLock.RUnlock()
+	if dec, ok := decoders[mt]; ok {
+		return dec, nil
+	}
+	for suffix, dec := range suffixDecoders {
+		if strings.HasSuffix(mt, suffix) {
+			return dec, nil
+		}
+	}
+	return nil, output.WrapError(&UnsupportedMediaTypeError{MediaType: mt}, http.StatusUnsupportedMediaType)
+}
+
+// DecodeYAML is Decoder for YAML documents
+func DecodeYAML(r io.Reader, v interface{}, o DecoderOptions) error {
+	// yaml.v3 turns errors of reader into plain strings, so body is read upfront
+	// to preserve errors such as *http.MaxBytesError
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(o.DisallowUnknownFields)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if o.RejectTrailingData {
+		var next yaml.Node
+		if err := dec.Decode(&next); err != io.EOF {
+			return errors.Join(ErrTrailingData, err)
+		}
+	}
+	return nil
+}
+
+// DecodeXML is Decoder for XML documents
+func DecodeXML(r io.Reader, v interface{}, o DecoderOptions) error {
+	dec := xml.NewDecoder(r)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if o.RejectTrailingData {
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return errors.Join(ErrTrailingData, err)
+			}
+			if cd, ok := tok.(xml.CharData); ok && len(strings.TrimSpace(string(cd))) == 0 {
+				continue
+			}
+			return ErrTrailingData
+		}
+	}
+	return nil
+}
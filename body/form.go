@@ -0,0 +1,157 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package body
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrFormTarget = errors.New("form can only be decoded into struct or map[string]string or map[string][]string")
+
+// DecodeForm is Decoder for application/x-www-form-urlencoded bodies.
+// Target can be map[string]string, map[string][]string or struct.
+// Struct fields are matched using "form" tag, then "json" tag and then field name.
+// Supported field types are strings, booleans, numbers, slices and pointers of those.
+func DecodeForm(r io.Reader, v interface{}, o DecoderOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case *map[string][]string:
+		*t = values
+		return nil
+	case *map[string]string:
+		m := make(map[string]string, len(values))
+		for k := range values {
+			m[k] = values.Get(k)
+		}
+		*t = m
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return ErrFormTarget
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	used := make(map[string]bool, len(values))
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := formFieldName(sf)
+		if name == "-" {
+			continue
+		}
+		vals, ok := values[name]
+		if !ok {
+			continue
+		}
+		used[name] = true
+		if err = setFormField(rv.Field(i), vals); err != nil {
+			return fmt.Errorf("form field %s: %w", name, err)
+		}
+	}
+	if o.DisallowUnknownFields {
+		for k := range values {
+			if !used[k] {
+				return fmt.Errorf("form: unknown field %q", k)
+			}
+		}
+	}
+	return nil
+}
+
+func formFieldName(sf reflect.StructField) string {
+	for _, tag := range []string{"form", "json"} {
+		if v, ok := sf.Tag.Lookup(tag); ok {
+			if name, _, _ := strings.Cut(v, ","); len(name) > 0 {
+				return name
+			}
+		}
+	}
+	return sf.Name
+}
+
+func setFormField(fv reflect.Value, vals []string) error {
+	switch fv.Kind() {
+	case reflect.Pointer:
+		nv := reflect.New(fv.Type().Elem())
+		if err := setFormField(nv.Elem(), vals); err != nil {
+			return err
+		}
+		fv.Set(nv)
+		return nil
+	case reflect.Slice:
+		sl := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, s := range vals {
+			if err := setFormValue(sl.Index(i), s); err != nil {
+				return err
+			}
+		}
+		fv.Set(sl)
+		return nil
+	default:
+		return setFormValue(fv, vals[0])
+	}
+}
+
+func setFormValue(fv reflect.Value, s string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,475 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package body
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+const (
+	// MergePatchContentType is media type of JSON Merge Patch document (RFC 7386)
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is media type of JSON Patch document (RFC 6902)
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+var (
+	ErrPatchTestFailed       = errors.New("test operation failed")
+	ErrPatchPathNotFound     = errors.New("path not found")
+	ErrPatchInvalidPointer   = errors.New("invalid JSON pointer")
+	ErrPatchInvalidOperation = errors.New("invalid patch operation")
+)
+
+// PatchError describes failure of single JSON Patch operation.
+// Use errors.Is with ErrPatchTestFailed, ErrPatchPathNotFound, ErrPatchInvalidPointer
+// and ErrPatchInvalidOperation to find out the cause.
+type PatchError struct {
+	// Index is position of failed operation within patch document
+	Index int
+	// Op is name of failed operation
+	Op string
+	// Path is target path of failed operation
+	Path string
+	// Err is cause of failure
+	Err error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation #%d (%s %s): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// patchStatus returns HTTP status code that corresponds to patch error
+func patchStatus(err error) int {
+	if errors.Is(err, ErrPatchTestFailed) {
+		return http.StatusConflict
+	}
+	return http.StatusUnprocessableEntity
+}
+
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func unmarshalValue(data []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ApplyMergePatch applies JSON Merge Patch (RFC 7386) to JSON document.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	d, err := unmarshalValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := unmarshalValue(patch)
+	if err != nil {
+		return nil, output.WrapError(err, http.StatusBadRequest)
+	}
+	return json.Marshal(mergePatch(d, p))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = map[string]interface{}{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+		} else {
+			tm[k] = mergePatch(tm[k], v)
+		}
+	}
+	return tm
+}
+
+// ApplyJSONPatch applies JSON Patch (RFC 6902) to JSON document.
+// Operations are applied in order, and if any of them fails, error of type *PatchError
+// (wrapped in output.StatusError with status 409 for failed test, 422 otherwise) is returned.
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	d, err := unmarshalValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	var ops []patchOp
+	if err = json.Unmarshal(patch, &ops); err != nil {
+		return nil, output.WrapError(err, http.StatusBadRequest)
+	}
+	for i, op := range ops {
+		if d, err = applyOp(d, op); err != nil {
+			pe := &PatchError{Index: i, Op: op.Op, Err: err}
+			if op.Path != nil {
+				pe.Path = *op.Path
+			}
+			return nil, output.WrapError(pe, patchStatus(err))
+		}
+	}
+	return json.Marshal(d)
+}
+
+func applyOp(doc interface{}, op patchOp) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrPatchInvalidOperation)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		from []string
+		val  interface{}
+	)
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrPatchInvalidOperation)
+		}
+		if val, err = unmarshalValue(op.Value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPatchInvalidOperation, err)
+		}
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrPatchInvalidOperation)
+		}
+		if from, err = parsePointer(*op.From); err != nil {
+			return nil, err
+		}
+	}
+	switch op.Op {
+	case "add":
+		return addValue(doc, path, val)
+	case "remove":
+		doc, _, err = removeValue(doc, path)
+		return doc, err
+	case "replace":
+		if _, err = getValue(doc, path); err != nil {
+			return nil, err
+		}
+		if doc, _, err = removeValue(doc, path); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, val)
+	case "move":
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: can't move value into its own child", ErrPatchInvalidOperation)
+		}
+		if doc, val, err = removeValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, val)
+	case "copy":
+		if val, err = getValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, deepCopy(val))
+	case "test":
+		var actual interface{}
+		if actual, err = getValue(doc, path); err != nil {
+			return nil, err
+		}
+		if !jsonEqual(actual, val) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrPatchInvalidOperation, op.Op)
+	}
+}
+
+// parsePointer parses JSON Pointer (RFC 6901) into reference tokens
+func parsePointer(p string) ([]string, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("%w: %q", ErrPatchInvalidPointer, p)
+	}
+	toks := strings.Split(p[1:], "/")
+	for i, t := range toks {
+		toks[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return toks, nil
+}
+
+func isPrefix(prefix, toks []string) bool {
+	if len(prefix) > len(toks) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != toks[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex parses array index token, allowing index equal to length when allowEnd is true.
+func arrayIndex(tok string, length int, allowEnd bool) (int, error) {
+	if allowEnd && tok == "-" {
+		return length, nil
+	}
+	if len(tok) == 0 || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("%w: bad array index %q", ErrPatchInvalidPointer, tok)
+	}
+	idx, err := strconv.Atoi(tok)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("%w: bad array index %q", ErrPatchInvalidPointer, tok)
+	}
+	if idx > length || (!allowEnd && idx == length) {
+		return 0, fmt.Errorf("%w: array index %d out of bounds", ErrPatchPathNotFound, idx)
+	}
+	return idx, nil
+}
+
+func getValue(node interface{}, toks []string) (interface{}, error) {
+	for _, tok := range toks {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPatchPathNotFound, tok)
+			}
+			node = v
+		case []interface{}:
+			idx, err := arrayIndex(tok, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[idx]
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrPatchPathNotFound, tok)
+		}
+	}
+	return node, nil
+}
+
+func addValue(node interface{}, toks []string, val interface{}) (interface{}, error) {
+	if len(toks) == 0 {
+		return val, nil
+	}
+	tok, rest := toks[0], toks[1:]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			n[tok] = val
+			return n, nil
+		}
+		child, ok := n[tok]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrPatchPathNotFound, tok)
+		}
+		nc, err := addValue(child, rest, val)
+		if err != nil {
+			return nil, err
+		}
+		n[tok] = nc
+		return n, nil
+	case []interface{}:
+		idx, err := arrayIndex(tok, len(n), len(rest) == 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			n = append(n, nil)
+			copy(n[idx+1:], n[idx:])
+			n[idx] = val
+			return n, nil
+		}
+		nc, err := addValue(n[idx], rest, val)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = nc
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrPatchPathNotFound, tok)
+	}
+}
+
+func removeValue(node interface{}, toks []string) (interface{}, interface{}, error) {
+	if len(toks) == 0 {
+		return nil, node, nil
+	}
+	tok, rest := toks[0], toks[1:]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tok]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %q", ErrPatchPathNotFound, tok)
+		}
+		if len(rest) == 0 {
+			delete(n, tok)
+			return n, child, nil
+		}
+		nc, removed, err := removeValue(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		n[tok] = nc
+		return n, removed, nil
+	case []interface{}:
+		idx, err := arrayIndex(tok, len(n), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := n[idx]
+			return append(n[:idx], n[idx+1:]...), removed, nil
+		}
+		nc, removed, err := removeValue(n[idx], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		n[idx] = nc
+		return n, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrPatchPathNotFound, tok)
+	}
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			out[k] = deepCopy(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = deepCopy(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	switch ta := a.(type) {
+	case map[string]interface{}:
+		tb, ok := b.(map[string]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, v := range ta {
+			if w, ok := tb[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		tb, ok := b.([]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !jsonEqual(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		tb, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fa, errA := ta.Float64()
+		fb, errB := tb.Float64()
+		if errA != nil || errB != nil {
+			return ta == tb
+		}
+		return fa == fb
+	default:
+		return a == b
+	}
+}
+
+// ApplyPatch applies patch document from request body to JSON representation of existing entity
+// and decodes the result into new instance of T. Existing entity is not modified.
+// Patch format is determined by Content-Type of request, supported are MergePatchContentType
+// and JSONPatchContentType. Other media types result in 415 Unsupported Media Type error.
+func ApplyPatch[T any](req *http.Request, existing *T, opts ...Option) (*T, error) {
+	o := newOptions(opts)
+	mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	var applyFn func(doc, patch []byte) ([]byte, error)
+	switch mt {
+	case MergePatchContentType:
+		applyFn = ApplyMergePatch
+	case JSONPatchContentType:
+		applyFn = ApplyJSONPatch
+	default:
+		return nil, output.WrapError(&UnsupportedMediaTypeError{MediaType: mt}, http.StatusUnsupportedMediaType)
+	}
+	var r io.Reader = req.Body
+	if o.maxBytes > 0 {
+		r = http.MaxBytesReader(nil, req.Body, o.maxBytes)
+	}
+	patch, err := io.ReadAll(r)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	doc, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = applyFn(doc, patch); err != nil {
+		return nil, err
+	}
+	var res T
+	if err = DecodeJSON(bytes.NewReader(doc), &res, o.DecoderOptions); err != nil {
+		return nil, output.WrapError(err, http.StatusUnprocessableEntity)
+	}
+	return &res, nil
+}
+
+// PatchEntityByKey looks up existing entity using provided key and applies patch document
+// from request body to it, see ApplyPatch.
+func PatchEntityByKey[T any, K comparable](req *http.Request, key K, existingSupplierFn func(K) (*T, error), opts ...Option) (*T, error) {
+	existing, err := existingSupplierFn(key)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyPatch[T](req, existing, opts...)
+}
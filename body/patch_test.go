@@ -0,0 +1,103 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package body
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	out, err := ApplyMergePatch(
+		[]byte(`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`),
+		[]byte(`{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`, string(out))
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		doc   string
+		patch string
+		exp   string
+	}{
+		{"add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc"]}]`, `{"foo":["bar",["abc"]]}`},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"copy", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{"test", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{"escaped pointer", `{"a/b":{"m~n":1}}`, `[{"op":"replace","path":"/a~1b/m~0n","value":null}]`, `{"a/b":{"m~n":null}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := ApplyJSONPatch([]byte(tc.doc), []byte(tc.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.exp, string(out))
+		})
+	}
+
+	t.Run("failed test", func(t *testing.T) {
+		_, err := ApplyJSONPatch([]byte(`{"baz":"qux"}`), []byte(`[{"op":"test","path":"/baz","value":"bar"}]`))
+		assert.ErrorIs(t, err, ErrPatchTestFailed)
+		var se *output.StatusError
+		assert.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusConflict, se.Status)
+	})
+	t.Run("missing path", func(t *testing.T) {
+		_, err := ApplyJSONPatch([]byte(`{"foo":"bar"}`), []byte(`[{"op":"add","path":"/baz/bat","value":"qux"}]`))
+		assert.ErrorIs(t, err, ErrPatchPathNotFound)
+		var pe *PatchError
+		assert.ErrorAs(t, err, &pe)
+		assert.Equal(t, "/baz/bat", pe.Path)
+		var se *output.StatusError
+		assert.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusUnprocessableEntity, se.Status)
+	})
+}
+
+func TestPatchEntityByKey(t *testing.T) {
+	type Employee struct {
+		Name   string `json:"name"`
+		Age    int    `json:"age"`
+		Salary int    `json:"salary"`
+	}
+	existing := &Employee{Name: "Bob", Age: 25, Salary: 100}
+
+	req, err := http.NewRequest(http.MethodPatch, "/employee/Bob", bytes.NewBufferString(`{"salary":0}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", MergePatchContentType)
+	out, err := PatchEntityByKey(req, "Bob", func(string) (*Employee, error) {
+		return existing, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &Employee{Name: "Bob", Age: 25}, out)
+	assert.Equal(t, 100, existing.Salary)
+
+	req, err = http.NewRequest(http.MethodPatch, "/employee/Bob", bytes.NewBufferString(`{"salary":0}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	_, err = ApplyPatch(req, existing)
+	var umt *UnsupportedMediaTypeError
+	assert.ErrorAs(t, err, &umt)
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"time"
@@ -28,25 +31,90 @@ import (
 var (
 	ErrListenAddressMissing = errors.New("server.listen_address is required")
 	ErrCorsBadMaxAge        = errors.New("invalid value of CORS max_age")
+	ErrTelemetryBadBuckets  = errors.New("telemetry histogram_buckets must be sorted in increasing order")
+	ErrTelemetryBadMaxSets  = errors.New("invalid value of telemetry max_label_sets")
+	ErrTimeoutBadDuration   = errors.New("timeout default and max must not be negative")
+	ErrTimeoutBadStatus     = errors.New("timeout status_code must be either 503 or 504")
+	ErrCompressionBadLevel  = errors.New("compression level must be between 0 and 9")
+	ErrCompressionBadSize   = errors.New("invalid value of compression min_size")
+	ErrRateLimitBadLimit    = errors.New("rate limit limit, burst and period must not be negative")
+	ErrRateLimitBadKey      = errors.New("invalid value of rate limit key")
+	ErrRateLimitBadRoute    = errors.New("rate limit route must have pattern and positive limit")
+	ErrBadRoutePattern      = errors.New("invalid route pattern")
 )
 
 const (
-	DefaultMetricPath = "/metrics"
+	DefaultMetricPath      = "/metrics"
+	DefaultShutdownTimeout = 30 * time.Second
+
+	// DefaultCompressionMinSize is minimal size of response body that is compressed, unless configured otherwise
+	DefaultCompressionMinSize = 1024
+
+	// DefaultRateLimitPeriod is period in which rate limit applies, unless configured otherwise
+	DefaultRateLimitPeriod = time.Second
+	// DefaultRateLimitKeyHeader is name of header used as rate limit key, unless configured otherwise
+	DefaultRateLimitKeyHeader = "X-API-Key"
 )
 
+// DefaultCompressionContentTypes are content types that are compressed, unless configured otherwise
+var DefaultCompressionContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"application/yaml",
+	"image/svg+xml",
+}
+
 func (t *TLSConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
 	pf.StringVar(&t.CertFile, prefix+"tls-cert-file", "", "TLS certificate file")
 	pf.StringVar(&t.KeyFile, prefix+"tls-key-file", "", "TLS key file")
+	pf.StringVar(&t.ClientCAFile, prefix+"tls-client-ca-file", t.ClientCAFile, "File with CA certificates used to verify client certificates")
+	pf.StringVar((*string)(&t.ClientAuth), prefix+"tls-client-auth", string(t.ClientAuth), "Client authentication mode, one of none, request, require, verify_if_given or verify")
+	pf.StringVar((*string)(&t.MinVersion), prefix+"tls-min-version", string(t.MinVersion), "Minimal accepted TLS version, one of 1.0, 1.1, 1.2 or 1.3")
+	pf.StringSliceVar(&t.CipherSuites, prefix+"tls-cipher-suite", t.CipherSuites, "Cipher suite enabled for TLS 1.2 and lower")
+	pf.StringSliceVar(&t.CurvePreferences, prefix+"tls-curve", t.CurvePreferences, "Key exchange mechanism, in order of preference")
 }
 
 func (t *TelemetryConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
 	pf.BoolVar(&t.Enabled, prefix+"telemetry-enabled", t.Enabled, "Whether to enable telemetry")
 	pf.StringVar(&t.Path, prefix+"telemetry-path", t.Path, "Telemetry path")
+	pf.Float64SliceVar(&t.HistogramBuckets, prefix+"telemetry-histogram-buckets", t.HistogramBuckets, "Upper bounds of request duration histogram buckets, in seconds")
+	pf.IntVar(&t.MaxLabelSets, prefix+"telemetry-max-label-sets", t.MaxLabelSets, "Maximum number of distinct label sets per metric")
+}
+
+func (t *TimeoutConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
+	pf.DurationVar(&t.Default, prefix+"timeout-default", t.Default, "Default amount of time allowed to handle single request")
+	pf.DurationVar(&t.Max, prefix+"timeout-max", t.Max, "Upper bound of timeout that can be requested by client")
+	pf.BoolVar(&t.HonorClientDeadline, prefix+"timeout-honor-client-deadline", t.HonorClientDeadline, "Whether client can request timeout using header")
+	pf.StringVar(&t.Header, prefix+"timeout-header", t.Header, "Name of request header that carries timeout requested by client")
+	pf.IntVar(&t.StatusCode, prefix+"timeout-status-code", t.StatusCode, "HTTP status code sent when timeout expires")
+}
+
+func (c *CompressionConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
+	pf.BoolVar(&c.Enabled, prefix+"compression-enabled", c.Enabled, "Whether to compress responses")
+	pf.IntVar(&c.MinSize, prefix+"compression-min-size", c.MinSize, "Minimal size of response body in bytes to be compressed")
+	pf.IntVar(&c.Level, prefix+"compression-level", c.Level, "Compression level, from 1 (best speed) to 9 (best compression)")
+	pf.StringSliceVar(&c.ContentTypes, prefix+"compression-content-type", c.ContentTypes, "Content type that is compressed")
+}
+
+func (r *RateLimitConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
+	pf.BoolVar(&r.Enabled, prefix+"rate-limit-enabled", r.Enabled, "Whether to enable rate limiting")
+	pf.IntVar(&r.Limit, prefix+"rate-limit-limit", r.Limit, "Number of requests allowed per period")
+	pf.DurationVar(&r.Period, prefix+"rate-limit-period", r.Period, "Period in which rate limit applies")
+	pf.IntVar(&r.Burst, prefix+"rate-limit-burst", r.Burst, "Maximum number of requests allowed at once")
+	pf.StringVar((*string)(&r.Key), prefix+"rate-limit-key", string(r.Key), "What requests are limited by, one of ip, header or principal")
+	pf.StringVar(&r.KeyHeader, prefix+"rate-limit-key-header", r.KeyHeader, "Name of request header used as rate limit key")
 }
 
 func (c *CorsConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
 	pf.IntVar(&c.MaxAge, prefix+"cors-max-age", c.MaxAge, "CORS MaxAge value")
 	pf.StringSliceVar(&c.AllowedOrigins, prefix+"cors-allowed-origin", c.AllowedOrigins, "CORS allowed origin")
+	pf.StringSliceVar(&c.AllowedMethods, prefix+"cors-allowed-method", c.AllowedMethods, "CORS allowed method")
+	pf.StringSliceVar(&c.AllowedHeaders, prefix+"cors-allowed-header", c.AllowedHeaders, "CORS allowed header")
+	pf.StringSliceVar(&c.ExposedHeaders, prefix+"cors-exposed-header", c.ExposedHeaders, "CORS exposed header")
+	pf.BoolVar(&c.AllowCredentials, prefix+"cors-allow-credentials", c.AllowCredentials, "Whether CORS requests can include credentials")
 }
 
 func (s *ServerConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
@@ -56,6 +124,10 @@ func (s *ServerConfig) BindFlags(prefix string, pf *pflag.FlagSet) {
 	pf.DurationVar(s.ReadHeaderTimeout, prefix+"read-header-timeout", 30*time.Second, "Amount of time allowed to read request headers")
 	pf.DurationVar(s.WriteTimeout, prefix+"write-timeout", 30*time.Second, "Maximum duration before timing out writes of the response")
 	pf.DurationVar(s.IdleTimeout, prefix+"idle-timeout", 30*time.Second, "Maximum amount of time to wait for the next request when keep-alives are enabled")
+	if s.ShutdownTimeout == nil {
+		s.ShutdownTimeout = new(time.Duration)
+	}
+	pf.DurationVar(s.ShutdownTimeout, prefix+"shutdown-timeout", DefaultShutdownTimeout, "Maximum amount of time to wait for in-flight requests to complete during shutdown")
 }
 
 // Check checks if configuration is semantically valid
@@ -69,6 +141,42 @@ func (s *ServerConfig) Check() error {
 		if len(s.Telemetry.Path) == 0 {
 			s.Telemetry.Path = DefaultMetricPath
 		}
+		if s.Telemetry.MaxLabelSets < 0 {
+			return ErrTelemetryBadMaxSets
+		}
+		for i := 1; i < len(s.Telemetry.HistogramBuckets); i++ {
+			if s.Telemetry.HistogramBuckets[i] <= s.Telemetry.HistogramBuckets[i-1] {
+				return ErrTelemetryBadBuckets
+			}
+		}
+	}
+	if s.Compression != nil {
+		if s.Compression.Level < 0 || s.Compression.Level > 9 {
+			return ErrCompressionBadLevel
+		}
+		if s.Compression.MinSize < 0 {
+			return ErrCompressionBadSize
+		}
+	}
+	if s.RateLimit != nil {
+		if err := s.RateLimit.check(); err != nil {
+			return err
+		}
+	}
+	if s.TLS != nil {
+		if err := s.TLS.check(); err != nil {
+			return err
+		}
+	}
+	if s.Timeout != nil {
+		if s.Timeout.Default < 0 || s.Timeout.Max < 0 {
+			return ErrTimeoutBadDuration
+		}
+		switch s.Timeout.StatusCode {
+		case 0, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		default:
+			return ErrTimeoutBadStatus
+		}
 	}
 	if s.ListenAddress == "" {
 		return ErrListenAddressMissing
@@ -76,6 +184,43 @@ func (s *ServerConfig) Check() error {
 	return nil
 }
 
+func (r *RateLimitConfig) check() error {
+	if r.Limit < 0 || r.Burst < 0 || r.Period < 0 {
+		return ErrRateLimitBadLimit
+	}
+	if len(r.Key) > 0 && !r.Key.Valid() {
+		return ErrRateLimitBadKey
+	}
+	for _, route := range r.Routes {
+		if len(route.Pattern) == 0 || route.Limit <= 0 {
+			return ErrRateLimitBadRoute
+		}
+		if route.Burst < 0 || route.Period < 0 {
+			return ErrRateLimitBadLimit
+		}
+	}
+	patterns := make([]string, len(r.Routes))
+	for i, route := range r.Routes {
+		patterns[i] = route.Pattern
+	}
+	return CheckRoutePatterns(patterns...)
+}
+
+// CheckRoutePatterns checks that patterns are valid http.ServeMux patterns
+// and that none of them conflicts with another one.
+func CheckRoutePatterns(patterns ...string) (err error) {
+	mux := http.NewServeMux()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrBadRoutePattern, r)
+		}
+	}()
+	for _, p := range patterns {
+		mux.Handle(p, http.NotFoundHandler())
+	}
+	return nil
+}
+
 func (s *ServerConfig) isTls() bool {
 	if s.TLS == nil {
 		return false
@@ -83,6 +228,11 @@ func (s *ServerConfig) isTls() bool {
 	return len(s.TLS.CertFile) > 0 && len(s.TLS.KeyFile) > 0
 }
 
+// RunUntil configures srv from this config and serves it until stopCh is closed.
+// When TLS is configured, it is applied on top of srv.TLSConfig, if any.
+// Once stopCh is closed, server stops accepting new connections and waits for in-flight requests
+// to complete for at most ShutdownTimeout (DefaultShutdownTimeout if not set).
+// Connections that are still active after that are forcibly closed.
 func (s *ServerConfig) RunUntil(srv *http.Server, stopCh <-chan struct{}) error {
 	var (
 		err error
@@ -100,21 +250,53 @@ func (s *ServerConfig) RunUntil(srv *http.Server, stopCh <-chan struct{}) error
 	if s.WriteTimeout != nil {
 		srv.WriteTimeout = *s.WriteTimeout
 	}
+	if s.isTls() {
+		if srv.TLSConfig == nil {
+			srv.TLSConfig = &tls.Config{}
+		}
+		if err = s.TLS.Apply(srv.TLSConfig); err != nil {
+			return err
+		}
+	}
 	if l, err = net.Listen("tcp", s.ListenAddress); err != nil {
 		return err
 	}
+	errCh := make(chan error, 1)
 	go func() {
 		if !s.isTls() {
-			err = http.Serve(l, srv.Handler)
+			errCh <- srv.Serve(l)
 		} else {
-			err = http.ServeTLS(l, srv.Handler, s.TLS.CertFile, s.TLS.KeyFile)
+			errCh <- srv.ServeTLS(l, s.TLS.CertFile, s.TLS.KeyFile)
 		}
 	}()
-	<-stopCh
-	_ = l.Close()
+	select {
+	case err = <-errCh:
+		return err
+	case <-stopCh:
+	}
+	if err = s.shutdown(srv); err != nil {
+		return err
+	}
+	if err = <-errCh; errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
 	return err
 }
 
+// shutdown gracefully shuts down srv, forcibly closing remaining connections once timeout expires.
+func (s *ServerConfig) shutdown(srv *http.Server) error {
+	timeout := DefaultShutdownTimeout
+	if s.ShutdownTimeout != nil {
+		timeout = *s.ShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		return errors.Join(err, srv.Close())
+	}
+	return nil
+}
+
 func (s *ServerConfig) RunForever(srv *http.Server) error {
 	return s.RunUntil(srv, make(chan struct{}))
 }
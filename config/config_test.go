@@ -18,9 +18,13 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -47,6 +51,71 @@ func TestConfigCheck(t *testing.T) {
 		assert.NoError(t, c.Check())
 		assert.Equal(t, DefaultMetricPath, c.Telemetry.Path)
 	})
+	t.Run("Telemetry buckets not sorted", func(t *testing.T) {
+		c = &ServerConfig{Telemetry: &TelemetryConfig{HistogramBuckets: []float64{0.1, 0.05}}, ListenAddress: ":8080"}
+		assert.ErrorIs(t, c.Check(), ErrTelemetryBadBuckets)
+	})
+	t.Run("Compression level", func(t *testing.T) {
+		c = &ServerConfig{Compression: &CompressionConfig{Enabled: true, Level: 10}, ListenAddress: ":8080"}
+		assert.ErrorIs(t, c.Check(), ErrCompressionBadLevel)
+		c.Compression.Level = 9
+		assert.NoError(t, c.Check())
+	})
+	t.Run("Rate limit", func(t *testing.T) {
+		c = &ServerConfig{RateLimit: &RateLimitConfig{Enabled: true, Key: "cookie"}, ListenAddress: ":8080"}
+		assert.ErrorIs(t, c.Check(), ErrRateLimitBadKey)
+		c.RateLimit.Key = RateLimitConfigKeyIp
+		c.RateLimit.Routes = []RateLimitRoute{{Pattern: "/api/"}}
+		assert.ErrorIs(t, c.Check(), ErrRateLimitBadRoute)
+		c.RateLimit.Routes[0].Limit = 10
+		assert.NoError(t, c.Check())
+		c.RateLimit.Routes = append(c.RateLimit.Routes, RateLimitRoute{Pattern: "/api/", Limit: 5})
+		assert.ErrorIs(t, c.Check(), ErrBadRoutePattern)
+		c.RateLimit.Routes[1].Pattern = "GET /{"
+		assert.ErrorIs(t, c.Check(), ErrBadRoutePattern)
+	})
+	t.Run("TLS", func(t *testing.T) {
+		c = &ServerConfig{TLS: &TLSConfig{ClientAuth: "optional"}, ListenAddress: ":8080"}
+		assert.ErrorIs(t, c.Check(), ErrTLSBadClientAuth)
+		c.TLS.ClientAuth = TLSConfigClientAuthVerify
+		assert.ErrorIs(t, c.Check(), ErrTLSClientCAMissing)
+		c.TLS.ClientAuth = TLSConfigClientAuthRequest
+		c.TLS.MinVersion = "1.4"
+		assert.ErrorIs(t, c.Check(), ErrTLSBadMinVersion)
+		c.TLS.MinVersion = TLSConfigMinVersionN13
+		c.TLS.CipherSuites = []string{"TLS_RSA_WITH_RC4_128_SHA"}
+		assert.ErrorIs(t, c.Check(), ErrTLSBadCipherSuite)
+		c.TLS.CipherSuites = []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}
+		c.TLS.CurvePreferences = []string{"P224"}
+		assert.ErrorIs(t, c.Check(), ErrTLSBadCurve)
+		c.TLS.CurvePreferences = []string{"X25519", "P256"}
+		assert.NoError(t, c.Check())
+
+		tc := &tls.Config{}
+		assert.NoError(t, c.TLS.Apply(tc))
+		assert.Equal(t, tls.RequestClientCert, tc.ClientAuth)
+		assert.Equal(t, uint16(tls.VersionTLS13), tc.MinVersion)
+		assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tc.CipherSuites)
+		assert.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256}, tc.CurvePreferences)
+
+		// unset fields are left intact
+		tc = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS13}
+		assert.NoError(t, (&TLSConfig{}).Apply(tc))
+		assert.Equal(t, tls.RequireAnyClientCert, tc.ClientAuth)
+		assert.Equal(t, uint16(tls.VersionTLS13), tc.MinVersion)
+
+		c.TLS.ClientCAFile = filepath.Join(t.TempDir(), "ca.pem")
+		assert.NoError(t, os.WriteFile(c.TLS.ClientCAFile, []byte("not a certificate"), 0o600))
+		assert.ErrorIs(t, c.TLS.Apply(tc), ErrTLSBadClientCA)
+	})
+	t.Run("Timeout status code", func(t *testing.T) {
+		c = &ServerConfig{Timeout: &TimeoutConfig{Default: time.Second, StatusCode: 500}, ListenAddress: ":8080"}
+		assert.ErrorIs(t, c.Check(), ErrTimeoutBadStatus)
+		c.Timeout.StatusCode = 504
+		assert.NoError(t, c.Check())
+		c.Timeout.Max = -time.Second
+		assert.ErrorIs(t, c.Check(), ErrTimeoutBadDuration)
+	})
 }
 
 func TestRunUntil(t *testing.T) {
@@ -76,6 +145,83 @@ func TestRunUntil(t *testing.T) {
 	assert.False(t, isRunning)
 }
 
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer func() {
+		_ = l.Close()
+	}()
+	return l.Addr().String()
+}
+
+func TestRunUntilGracefulShutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	stopCh := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	cfg := &ServerConfig{ListenAddress: freeAddress(t)}
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- cfg.RunUntil(srv, stopCh)
+	}()
+
+	var resp *http.Response
+	respErr := make(chan error, 1)
+	go func() {
+		var err error
+		assert.NoError(t, retry.Do(func() error {
+			resp, err = http.Get("http://" + cfg.ListenAddress)
+			return err
+		}, retry.Attempts(10), retry.Delay(time.Millisecond*100)))
+		respErr <- err
+	}()
+
+	<-started
+	close(stopCh)
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	assert.NoError(t, <-respErr)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.NoError(t, <-runErr)
+}
+
+func TestRunUntilShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	stopCh := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+	timeout := 100 * time.Millisecond
+	cfg := &ServerConfig{ListenAddress: freeAddress(t), ShutdownTimeout: &timeout}
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- cfg.RunUntil(srv, stopCh)
+	}()
+	go func() {
+		_ = retry.Do(func() error {
+			resp, err := http.Get("http://" + cfg.ListenAddress)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			return err
+		}, retry.Attempts(10), retry.Delay(time.Millisecond*100))
+	}()
+
+	<-started
+	close(stopCh)
+	assert.ErrorIs(t, <-runErr, context.DeadlineExceeded)
+}
+
 func bool2err(b bool, s string) error {
 	if !b {
 		return errors.New(s)
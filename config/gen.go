@@ -16,29 +16,194 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// Defines values for TLSConfigClientAuth.
+const (
+	TLSConfigClientAuthNone          TLSConfigClientAuth = "none"
+	TLSConfigClientAuthRequest       TLSConfigClientAuth = "request"
+	TLSConfigClientAuthRequire       TLSConfigClientAuth = "require"
+	TLSConfigClientAuthVerify        TLSConfigClientAuth = "verify"
+	TLSConfigClientAuthVerifyIfGiven TLSConfigClientAuth = "verify_if_given"
+)
+
+// Valid indicates whether the value is a known member of the TLSConfigClientAuth enum.
+func (e TLSConfigClientAuth) Valid() bool {
+	switch e {
+	case TLSConfigClientAuthNone:
+		return true
+	case TLSConfigClientAuthRequest:
+		return true
+	case TLSConfigClientAuthRequire:
+		return true
+	case TLSConfigClientAuthVerify:
+		return true
+	case TLSConfigClientAuthVerifyIfGiven:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for TLSConfigMinVersion.
+const (
+	TLSConfigMinVersionN10 TLSConfigMinVersion = "1.0"
+	TLSConfigMinVersionN11 TLSConfigMinVersion = "1.1"
+	TLSConfigMinVersionN12 TLSConfigMinVersion = "1.2"
+	TLSConfigMinVersionN13 TLSConfigMinVersion = "1.3"
+)
+
+// Valid indicates whether the value is a known member of the TLSConfigMinVersion enum.
+func (e TLSConfigMinVersion) Valid() bool {
+	switch e {
+	case TLSConfigMinVersionN10:
+		return true
+	case TLSConfigMinVersionN11:
+		return true
+	case TLSConfigMinVersionN12:
+		return true
+	case TLSConfigMinVersionN13:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for RateLimitConfigKey.
+const (
+	RateLimitConfigKeyHeader    RateLimitConfigKey = "header"
+	RateLimitConfigKeyIp        RateLimitConfigKey = "ip"
+	RateLimitConfigKeyPrincipal RateLimitConfigKey = "principal"
+)
+
+// Valid indicates whether the value is a known member of the RateLimitConfigKey enum.
+func (e RateLimitConfigKey) Valid() bool {
+	switch e {
+	case RateLimitConfigKeyHeader:
+		return true
+	case RateLimitConfigKeyIp:
+		return true
+	case RateLimitConfigKeyPrincipal:
+		return true
+	default:
+		return false
+	}
+}
+
 // TLSConfig TLS configuration
 type TLSConfig struct {
 	// CertFile Path to file with certificate bundle
 	CertFile string `json:"cert_file" yaml:"cert_file"`
 
+	// CipherSuites Names of cipher suites enabled for TLS 1.2 and lower, such as TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. TLS 1.3 cipher suites are not configurable
+	CipherSuites []string `json:"cipher_suites,omitempty" yaml:"cipher_suites,omitempty"`
+
+	// ClientAuth Client authentication mode. none (default) does not request client certificate, request and require ask for certificate without verifying it, verify_if_given and verify verify certificate against client CA bundle, the latter also requires it
+	ClientAuth TLSConfigClientAuth `json:"client_auth,omitempty" yaml:"client_auth,omitempty"`
+
+	// ClientCAFile Path to file with bundle of CA certificates used to verify client certificates
+	ClientCAFile string `json:"client_ca_file,omitempty" yaml:"client_ca_file,omitempty"`
+
+	// CurvePreferences Names of key exchange mechanisms in order of preference, such as X25519MLKEM768, X25519 or P256
+	CurvePreferences []string `json:"curve_preferences,omitempty" yaml:"curve_preferences,omitempty"`
+
 	// KeyFile Path to file with private key
 	KeyFile string `json:"key_file" yaml:"key_file"`
+
+	// MinVersion Minimal accepted TLS version, defaults to 1.2
+	MinVersion TLSConfigMinVersion `json:"min_version,omitempty" yaml:"min_version,omitempty"`
+}
+
+// TLSConfigClientAuth Client authentication mode. none (default) does not request client certificate, request and require ask for certificate without verifying it, verify_if_given and verify verify certificate against client CA bundle, the latter also requires it
+type TLSConfigClientAuth string
+
+// TLSConfigMinVersion Minimal accepted TLS version, defaults to 1.2
+type TLSConfigMinVersion string
+
+// CompressionConfig Response compression configuration
+type CompressionConfig struct {
+	// ContentTypes Content types that are compressed, such as application/json or text/*
+	ContentTypes []string `json:"content_types,omitempty" yaml:"content_types,omitempty"`
+
+	// Enabled Whether responses should be compressed
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Level Compression level, from 1 (best speed) to 9 (best compression), zero means default
+	Level int `json:"level,omitempty" yaml:"level,omitempty"`
+
+	// MinSize Minimal size of response body in bytes to be compressed
+	MinSize int `json:"min_size,omitempty" yaml:"min_size,omitempty"`
 }
 
 // CorsConfig CORS configuration
 type CorsConfig struct {
+	// AllowCredentials AllowCredentials controls value of Access-Control-Allow-Credentials header.
+	AllowCredentials bool `json:"allow_credentials,omitempty" yaml:"allow_credentials,omitempty"`
+
+	// AllowedHeaders AllowedHeaders controls value of Access-Control-Allow-Headers header.
+	AllowedHeaders []string `json:"allowed_headers,omitempty" yaml:"allowed_headers,omitempty"`
+
+	// AllowedMethods AllowedMethods controls value of Access-Control-Allow-Methods header.
+	AllowedMethods []string `json:"allowed_methods,omitempty" yaml:"allowed_methods,omitempty"`
+
 	// AllowedOrigins AllowedOrigins controls value of Access-Control-Allow-Origin header.
 	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
 
+	// ExposedHeaders ExposedHeaders controls value of Access-Control-Expose-Headers header.
+	ExposedHeaders []string `json:"exposed_headers,omitempty" yaml:"exposed_headers,omitempty"`
+
 	// MaxAge MaxAge controls value of Access-Control-Max-Age header.
 	MaxAge int `json:"max_age" yaml:"max_age"`
 }
 
+// RateLimitConfig Rate limiting configuration
+type RateLimitConfig struct {
+	// Burst Maximum number of requests allowed at once, defaults to limit
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+
+	// Enabled Whether rate limiting should be enabled
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Key What requests are limited by, one of ip, header or principal
+	Key RateLimitConfigKey `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// KeyHeader Name of request header used as key when key is header
+	KeyHeader string `json:"key_header,omitempty" yaml:"key_header,omitempty"`
+
+	// Limit Number of requests allowed per period, zero means no limit for requests that don't match any route
+	Limit int `json:"limit,omitempty" yaml:"limit,omitempty"`
+
+	// Period Period in which limit applies
+	Period time.Duration `json:"period,omitempty" yaml:"period,omitempty"`
+
+	// Routes Limits for specific routes
+	Routes []RateLimitRoute `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// RateLimitConfigKey What requests are limited by, one of ip, header or principal
+type RateLimitConfigKey string
+
+// RateLimitRoute Rate limit of single route
+type RateLimitRoute struct {
+	// Burst Maximum number of requests allowed at once, defaults to limit
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+
+	// Limit Number of requests allowed per period
+	Limit int `json:"limit" yaml:"limit"`
+
+	// Pattern Route pattern, using syntax of http.ServeMux
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	// Period Period in which limit applies
+	Period time.Duration `json:"period,omitempty" yaml:"period,omitempty"`
+}
+
 // ServerConfig Server configuration
 type ServerConfig struct {
 	// APIPrefix API prefix
 	APIPrefix string `json:"api_prefix" yaml:"api_prefix"`
 
+	// Compression Response compression configuration
+	Compression *CompressionConfig `json:"compression,omitempty" yaml:"compression,omitempty"`
+
 	// Cors CORS configuration
 	Cors *CorsConfig `json:"cors,omitempty" yaml:"cors,omitempty"`
 
@@ -48,15 +213,24 @@ type ServerConfig struct {
 	// ListenAddress Address to listen on
 	ListenAddress string `json:"listen_address" yaml:"listen_address"`
 
+	// RateLimit Rate limiting configuration
+	RateLimit *RateLimitConfig `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+
 	// ReadHeaderTimeout HTTP headers receive timeout
 	ReadHeaderTimeout *time.Duration `json:"read_header_timeout,omitempty" yaml:"read_header_timeout,omitempty"`
 
 	// ReadTimeout HTTP read timeout
 	ReadTimeout *time.Duration `json:"read_timeout,omitempty" yaml:"read_timeout,omitempty"`
 
+	// ShutdownTimeout Graceful shutdown timeout
+	ShutdownTimeout *time.Duration `json:"shutdown_timeout,omitempty" yaml:"shutdown_timeout,omitempty"`
+
 	// Telemetry Telemetry configuration
 	Telemetry *TelemetryConfig `json:"telemetry,omitempty" yaml:"telemetry,omitempty"`
 
+	// Timeout Request timeout configuration
+	Timeout *TimeoutConfig `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
 	// TLS TLS configuration
 	TLS *TLSConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
 
@@ -69,26 +243,71 @@ type TelemetryConfig struct {
 	// Enabled Whether the metrics instrumentation should be enabled
 	Enabled bool `json:"enabled" yaml:"enabled"`
 
+	// HistogramBuckets Upper bounds of buckets used by request duration histograms, in seconds
+	HistogramBuckets []float64 `json:"histogram_buckets,omitempty" yaml:"histogram_buckets,omitempty"`
+
+	// MaxLabelSets Maximum number of distinct label sets per metric, zero means unlimited
+	MaxLabelSets int `json:"max_label_sets,omitempty" yaml:"max_label_sets,omitempty"`
+
 	// Path HTTP context where metrics should be exposed
 	Path string `json:"path" yaml:"path"`
 }
 
+// TimeoutConfig Request timeout configuration
+type TimeoutConfig struct {
+	// Default Default amount of time allowed to handle single request
+	Default time.Duration `json:"default" yaml:"default"`
+
+	// Header Name of request header that carries timeout requested by client
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+
+	// HonorClientDeadline Whether client can request shorter or longer timeout using header
+	HonorClientDeadline bool `json:"honor_client_deadline,omitempty" yaml:"honor_client_deadline,omitempty"`
+
+	// Max Upper bound of timeout that can be requested by client
+	Max time.Duration `json:"max,omitempty" yaml:"max,omitempty"`
+
+	// StatusCode HTTP status code sent when timeout expires, either 503 or 504
+	StatusCode int `json:"status_code,omitempty" yaml:"status_code,omitempty"`
+}
+
 // Base64 encoded, compressed with deflate, json marshaled OpenAPI spec.
 // Stored as a slice of fixed-width chunks rather than one concatenated
 // const string: with thousands of chunks the chained `+` fold is several
 // times slower for the Go compiler than parsing a slice literal.
 var swaggerSpec = []string{
-	"nFXBbtswDP0VgdvRSQNsp9yK7rACLRqsAXYYhkCxaZurLHkUnTgo8u+DbKdO42TecrPFx0fykZReIXZF",
-	"6Sxa8TB/BR/nWOjmc/nwfOdsSln40UlCQs5qs2BXIguhh3mqjccIEvQxUxnsMA9+Km4cK9bNWQTlkdMr",
-	"xMiySslg+HnvvNCSK3EqWNWWJFcBTCnFWlCtK5sYhAhkVyLMwQuTzWAfwQvu/pmxZNoEthfcDan2ETD+",
-	"rogxgfmPo1SPYvx883LrXxhLSCB27K+S6+7p25he2hi3xWTlmDKyfljjbQt4au2BTdgZrzbaVKhcqm7j",
-	"GL2f3LWGSYOftHCVo06QpxABCRYN+0De7kAz6134L3S90tkZtR91fZvheAaPup4EYB+8C0FWMEMeNOJU",
-	"gz6Hc93wyBvkvh/vk3xurGOil7QqGVOqz+i9uFed7XSAIqgnmZtYXWALXLS4bkQC10fGFObw4aZfvZtu",
-	"726OxmgfASUGV0IFukqGWdwnBtXBeiGP7jCgpl8Ope4jMOQF7UonCaM/N1GtISxOC1WNRIPBYNTJqm3i",
-	"5US/LpeLrtNeMcZIm6sTbwL+PVKAXEsvaLBA4d1Yo96AfbfEjLa3v1Pfz8ny4TkwbJkER6prMNeVd7pS",
-	"/YQPBuLcUp2W/J/PwsF7ZO/Q6rUJCZ5W/z1HyZGV5KgCEcVekfXCVYFWGjLlc1eZRK1RHWje6lg7Z1A3",
-	"TS615BfUDRcX1qK2OXIf5oi2Lp0/pr3wbvThm2BDOYMD2dQ11y1JeLnO3kuqHR2IYIPs20xn09n0cyjE",
-	"lWh1STCHT9PZdNZFCzLu938CAAD//w==",
+	"zFltb+O4Ef4rA7ZAbwvFednL9s7fDG96WXTTCzYprkBRCLQ4tniRSJWkHPsW+e/FkJQl23L8krvDfdis",
+	"LA6H8/LM8CH1lWW6rLRC5SwbfmU2y7Hk/vHx88NYq6mc0Q8uhHRSK17cG12hcRItG055YTFhAm1mZEXj",
+	"bEjzIPMTa8P9u4RVnUlfWYbGpVNZIP1Yn3zPXQ5OA43Cs3Q5kLCcyow7hEmtRIEsYW5ZIRsy64xUM/aS",
+	"sExWOZrU1tKh3Vb7T16iBT2FIAdBDlDxSYECptoAmX05uAKuBBT6GU0Cts5y4JaG0pvxx9sb+vswSn/6",
+	"9Hibjm4e0sur79Ifxnfpw+3o6vrDIOp4v7EKNwhKuzYoE++DdFh6U7eciS+4MXzJErY4m+kzendmn2R1",
+	"pquQiLNKS+XQsKEzNVIMConKpbx2+XYExn4QaBCVo3BKraDUAgegtEL4RuCU14V7B0Kj9QYb/F+N1kFQ",
+	"3M1EshqjcNGzNAjcPvlQdlNGOdS1gzkaOV1KNQPpkvgrldN0JueovJbwrvmvq4PPuFStHeNRBEICLkco",
+	"uHNogBdWN5ZYkI4lDFVdsuF/GPnHEhZNjk/S0LsNQ1Zv2H83QXZ0HjJ+MMaDO4TP8ajruYXaoiDZJipb",
+	"mbBsh6GKl7jK+3j0d1lgM3SID7WZY1oZnKJBlb1aU0+4BFxkOVczhBLpQdrSglSgjUBDMq2mtqz+fXV9",
+	"ffn93ed/3Nz97cN3SfwN2sD91fWH36JCnnB5cE4qI+cEvidc9jWcUqp0jsb66Zva7qSSJS+AZxlWDoVv",
+	"DFE6gVholta7HFx1gHo5uGAJuxxc+r9X/u/706H4skK6IO1t2+1EolWuJz9j5nwz1WVl0JK1J+0AX9BW",
+	"WlmEjqJ9W4JWjoqGbOnB2jgMgx8Gl3Pnu2qjH0WLKl5VRWxv5z9bTRgEhwt3/tffAlFxB9m2+KccHe0B",
+	"JsbCgs11XQiYdM1ukTXRukCuyJQC51j0xaANphdJYGp0CZfwzYQ6sa0QxTsC1ffxTSf87xL4BY2GErmy",
+	"DQLb1cmpGZojHCf8W/kL7gY/jVLpNxGAiRZLagqTJXU2p3eF4lhjNmDepKQf2caeBOnxj1/2sRpeFPo5",
+	"zQwKVE7yogfGIxIZtxKk0RldWJjzovbRGmUZWns2DgNnfsZZd0qOXKAZbCPn8Nx5S1GkQdUuO1HchvFD",
+	"rWzEWwt/9XJrLC/R5VrstvwujB9qeSP+O1iujZxJtdvyH8P4oZYH8eMNp9a1qLR9DQU3QeBgFAT53wMG",
+	"JV+kfNbXe/hiNMP9pt7xxRkJbhVT03s2m8pm+lob+tqM4Q4/y1K607ZP7hAKmk50+fWmM6mNdb1xkGVd",
+	"gqrLSSBgkfxaiJ4Ad6A9HevSEb/qW7aF/fvhmnPtnthM7NsQiYL1KOSu45WJWlHAZJmAVj7tskpijokG",
+	"VEaqTFa86FAuWbGEBREf3EbiDfyfuFXU2EuaO+lojPM0n1tPpZ9zVP5BNjXETrclJHTbjN24qNDQP6nF",
+	"GmVQERz+gLea5JmY0OovDkruiICpJRhdO3wLiMLyPSTdvycK8ZzLLI8GecK3+xwUXzpZ4uBjW0aH2uKd",
+	"6WmOvrytj4atMKPTGETZTsP7s8EpG7I/nbe3LOfxiuV81SS+0LSTu+ER7GdjxVO7EsHGSjUrcJXqP3ZT",
+	"eksR9GwNCav8lUPPwc8HFuJwArX1TW6pHF/QOrlz1eABzRzv6kXfqfKPg/wNWDUeN8Hsg5clx0y75a27",
+	"4N02+zh0Jf2lg1z0EKT7TxDHXr/yGN1/ug9y62fZfQW5feyNJ4b9E1enipeESVFgSkHXdQ/oPokCoRk9",
+	"JnEex9ahSrkQZGZPhMJAqBkSBR/iLZQZ7jBdFcVBPap1zyBvOONuL28fH+/j5mXBYIZyfrLXfsHXVyKR",
+	"U9XbvHZCP6vdS/xgeIbTuoBG9NSlHBZYojPLfWFfCbZh71j36swg1plX7IVve9G/XkePnx9Iw7ORDvck",
+	"wMucFpZNot12gC3A9zWdzVAd+a2imb2nL+2ltXQRTYpkRvee1pm6ROW8sgNJbi6t0zPDy3RSZ0/oeur7",
+	"XxXtSxNdK+EvXqNgYI+T5YpUiugGrHTahPYOi5lWYo2gTLUpuWNDJnQ96X5aCZv0G89oBZ9gkdpeZ7bZ",
+	"gJDWSZU58NOApvmNOMR1jY3WKpL9N9FM7vIdePbXkQtHXNy0ie0kMhyNt5vrDjYWF+sF8FrFHnvRGhIe",
+	"dewBcXPrt+XyxzAAvNS18vSOFK7IkNOQc/+BomF9qw8pR7W/I89E/myRcWMk2pWHUSbgPXwNecPpKNdK",
+	"mzR+rxHIRSEV7i7y5usLVytbba6NC6fLQqsZmpWlgfttHuCOv6wr+eLVTtCki9aMIVME0SMj9YYjknXc",
+	"1TbNtMAd5RQkgCTAUgj9GbexGheVNGgTQOnDfH3xnuJ5ffHtr3Yn3EB/uwBJUqqpJsuddAXuoKoQdsvw",
+	"gTCwSXYxuBh8S8jWFSpeSTZk7wcXg4tY7lR0Ly//HwA=",
 }
 
 // decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
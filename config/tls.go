@@ -0,0 +1,133 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrTLSBadClientAuth   = errors.New("invalid value of TLS client_auth")
+	ErrTLSClientCAMissing = errors.New("TLS client_ca_file is required to verify client certificates")
+	ErrTLSBadClientCA     = errors.New("TLS client_ca_file contains no certificates")
+	ErrTLSBadMinVersion   = errors.New("invalid value of TLS min_version")
+	ErrTLSBadCipherSuite  = errors.New("unknown or insecure TLS cipher suite")
+	ErrTLSBadCurve        = errors.New("unknown TLS curve")
+)
+
+var (
+	tlsClientAuthModes = map[TLSConfigClientAuth]tls.ClientAuthType{
+		"":                               tls.NoClientCert,
+		TLSConfigClientAuthNone:          tls.NoClientCert,
+		TLSConfigClientAuthRequest:       tls.RequestClientCert,
+		TLSConfigClientAuthRequire:       tls.RequireAnyClientCert,
+		TLSConfigClientAuthVerifyIfGiven: tls.VerifyClientCertIfGiven,
+		TLSConfigClientAuthVerify:        tls.RequireAndVerifyClientCert,
+	}
+	tlsVersions = map[TLSConfigMinVersion]uint16{
+		"":                     tls.VersionTLS12,
+		TLSConfigMinVersionN10: tls.VersionTLS10,
+		TLSConfigMinVersionN11: tls.VersionTLS11,
+		TLSConfigMinVersionN12: tls.VersionTLS12,
+		TLSConfigMinVersionN13: tls.VersionTLS13,
+	}
+	tlsCurves = map[string]tls.CurveID{
+		"X25519MLKEM768": tls.X25519MLKEM768,
+		"X25519":         tls.X25519,
+		"P256":           tls.CurveP256,
+		"P384":           tls.CurveP384,
+		"P521":           tls.CurveP521,
+	}
+)
+
+func tlsCipherSuite(name string) (uint16, bool) {
+	for _, cs := range tls.CipherSuites() {
+		if cs.Name == name {
+			return cs.ID, true
+		}
+	}
+	return 0, false
+}
+
+func (t *TLSConfig) check() error {
+	mode, ok := tlsClientAuthModes[t.ClientAuth]
+	if !ok {
+		return ErrTLSBadClientAuth
+	}
+	if mode >= tls.VerifyClientCertIfGiven && len(t.ClientCAFile) == 0 {
+		return ErrTLSClientCAMissing
+	}
+	if _, ok = tlsVersions[t.MinVersion]; !ok {
+		return ErrTLSBadMinVersion
+	}
+	for _, name := range t.CipherSuites {
+		if _, ok = tlsCipherSuite(name); !ok {
+			return fmt.Errorf("%w: %s", ErrTLSBadCipherSuite, name)
+		}
+	}
+	for _, name := range t.CurvePreferences {
+		if _, ok = tlsCurves[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrTLSBadCurve, name)
+		}
+	}
+	return nil
+}
+
+// Apply sets client authentication, protocol version, cipher suites and curve preferences of cfg
+// according to this configuration. Fields that are not configured are left intact.
+// Certificate and key are not loaded, http.Server.ServeTLS takes care of that.
+// Verified client certificate is then available in http.Request.TLS, see auth.NewClientCertAuthenticator.
+func (t *TLSConfig) Apply(cfg *tls.Config) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	if len(t.ClientAuth) > 0 {
+		cfg.ClientAuth = tlsClientAuthModes[t.ClientAuth]
+	}
+	if len(t.MinVersion) > 0 {
+		cfg.MinVersion = tlsVersions[t.MinVersion]
+	}
+	if len(t.ClientCAFile) > 0 {
+		data, err := os.ReadFile(t.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return ErrTLSBadClientCA
+		}
+		cfg.ClientCAs = pool
+	}
+	if len(t.CipherSuites) > 0 {
+		cfg.CipherSuites = make([]uint16, 0, len(t.CipherSuites))
+		for _, name := range t.CipherSuites {
+			id, _ := tlsCipherSuite(name)
+			cfg.CipherSuites = append(cfg.CipherSuites, id)
+		}
+	}
+	if len(t.CurvePreferences) > 0 {
+		cfg.CurvePreferences = make([]tls.CurveID, 0, len(t.CurvePreferences))
+		for _, name := range t.CurvePreferences {
+			cfg.CurvePreferences = append(cfg.CurvePreferences, tlsCurves[name])
+		}
+	}
+	return nil
+}
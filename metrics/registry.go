@@ -0,0 +1,381 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics provides minimalistic, dependency-free metrics registry
+// that can be exposed in Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// OverflowLabelValue is used for all labels of series that exceeded cardinality limit.
+	OverflowLabelValue = "__overflow__"
+
+	// ContentType is content type of Prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// OtherMethod is method label value used for non-standard HTTP methods
+	OtherMethod = "OTHER"
+)
+
+// MethodLabel maps HTTP method to label value, so that arbitrary extension methods
+// don't lead to unbounded cardinality. Empty method is treated as GET, same as http.Client does.
+func MethodLabel(method string) string {
+	switch method {
+	case "":
+		return http.MethodGet
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return OtherMethod
+	}
+}
+
+var (
+	// DefaultBuckets are default histogram buckets, tailored to measure HTTP latency in seconds.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	defaultRegistry = NewRegistry()
+)
+
+// DefaultRegistry returns shared Registry instance
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+type metricKind string
+
+const (
+	kindCounter   metricKind = "counter"
+	kindGauge     metricKind = "gauge"
+	kindHistogram metricKind = "histogram"
+)
+
+// familyStore is collection of metric families, shared by Registry and its views
+type familyStore struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// Registry holds collection of metric families.
+type Registry struct {
+	*familyStore
+	maxSets int
+}
+
+// NewRegistry creates new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{familyStore: &familyStore{families: map[string]*family{}}}
+}
+
+// WithLabelLimit returns view of this Registry, which shares all metric families with it,
+// but limits number of distinct label sets of families created through it.
+// Observations that would exceed this limit are accounted to single series with all labels
+// set to OverflowLabelValue. Zero or negative value means no limit.
+// Families that already exist keep their limit, this Registry is not affected.
+func (r *Registry) WithLabelLimit(limit int) *Registry {
+	return &Registry{familyStore: r.familyStore, maxSets: limit}
+}
+
+func (r *Registry) getOrCreate(name, help string, kind metricKind, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind {
+			panic(fmt.Sprintf("metric %s already registered as %s", name, f.kind))
+		}
+		return f
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		maxSets: r.maxSets,
+		series:  map[string]*series{},
+	}
+	r.families[name] = f
+	return f
+}
+
+// Counter gets or creates counter family with given name and label names.
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.getOrCreate(name, help, kindCounter, nil, labels)}
+}
+
+// Gauge gets or creates gauge family with given name and label names.
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{f: r.getOrCreate(name, help, kindGauge, nil, labels)}
+}
+
+// Histogram gets or creates histogram family with given name, upper bounds of buckets and label names.
+// If buckets are empty, DefaultBuckets are used.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+	return &HistogramVec{f: r.getOrCreate(name, help, kindHistogram, b, labels)}
+}
+
+// WriteTo writes all metric families into w in Prometheus text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.families))
+	for n := range r.families {
+		names = append(names, n)
+	}
+	fams := make([]*family, 0, len(names))
+	sort.Strings(names)
+	for _, n := range names {
+		fams = append(fams, r.families[n])
+	}
+	r.mu.RUnlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, f := range fams {
+		f.write(cw)
+	}
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// Handler returns http.Handler that exposes content of this registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = r.WriteTo(w)
+	})
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) printf(format string, args ...any) {
+	if c.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(c.w, format, args...)
+	c.n += int64(n)
+	c.err = err
+}
+
+type series struct {
+	labels  []string
+	value   float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+	encoded string
+}
+
+type family struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	kind    metricKind
+	labels  []string
+	buckets []float64
+	maxSets int
+	series  map[string]*series
+}
+
+// get returns series for given label values, caller must hold the lock.
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	if s, ok := f.series[key]; ok {
+		return s
+	}
+	if f.maxSets > 0 && len(f.series) >= f.maxSets {
+		values = make([]string, len(f.labels))
+		for i := range values {
+			values[i] = OverflowLabelValue
+		}
+		key = strings.Join(values, "\xff")
+		if s, ok := f.series[key]; ok {
+			return s
+		}
+	}
+	s := &series{labels: values, encoded: encodeLabels(f.labels, values)}
+	if f.kind == kindHistogram {
+		s.counts = make([]uint64, len(f.buckets))
+	}
+	f.series[key] = s
+	return s
+}
+
+func (f *family) write(w *countingWriter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.series) == 0 {
+		return
+	}
+	if len(f.help) > 0 {
+		w.printf("# HELP %s %s\n", f.name, escapeHelp(f.help))
+	}
+	w.printf("# TYPE %s %s\n", f.name, f.kind)
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := f.series[k]
+		if f.kind != kindHistogram {
+			w.printf("%s%s %s\n", f.name, s.encoded, formatFloat(s.value))
+			continue
+		}
+		for i, b := range f.buckets {
+			w.printf("%s_bucket%s %d\n", f.name, withLabel(s.encoded, "le", formatFloat(b)), s.counts[i])
+		}
+		w.printf("%s_bucket%s %d\n", f.name, withLabel(s.encoded, "le", "+Inf"), s.count)
+		w.printf("%s_sum%s %s\n", f.name, s.encoded, formatFloat(s.sum))
+		w.printf("%s_count%s %d\n", f.name, s.encoded, s.count)
+	}
+}
+
+// CounterVec is family of counters partitioned by label values.
+type CounterVec struct {
+	f *family
+}
+
+// Add adds v to counter identified by label values. Negative values are ignored.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(labelValues).value += v
+}
+
+// Inc increments counter identified by label values.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// GaugeVec is family of gauges partitioned by label values.
+type GaugeVec struct {
+	f *family
+}
+
+// Set sets gauge identified by label values to v.
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value = v
+}
+
+// Add adds v to gauge identified by label values. v can be negative.
+func (g *GaugeVec) Add(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value += v
+}
+
+// Inc increments gauge identified by label values.
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements gauge identified by label values.
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// HistogramVec is family of histograms partitioned by label values.
+type HistogramVec struct {
+	f *family
+}
+
+// Observe records single observation into histogram identified by label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	s := h.f.get(labelValues)
+	for i, b := range h.f.buckets {
+		if v <= b {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func encodeLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(n)
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabelValue(values[i]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func withLabel(encoded, name, value string) string {
+	l := name + `="` + value + `"`
+	if len(encoded) == 0 {
+		return "{" + l + "}"
+	}
+	return encoded[:len(encoded)-1] + "," + l + "}"
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+func escapeHelp(v string) string {
+	return helpEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("requests_total", "Total requests", "method", "status")
+	c.Inc("GET", "200")
+	c.Inc("GET", "200")
+	c.Add(3, "POST", "500")
+	g := r.Gauge("in_flight", "In-flight requests")
+	g.Inc()
+	h := r.Histogram("latency_seconds", "Latency", []float64{0.5, 0.1}, "path")
+	h.Observe(0.05, `/a"b`)
+	h.Observe(0.3, `/a"b`)
+
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP in_flight In-flight requests
+# TYPE in_flight gauge
+in_flight 1
+# HELP latency_seconds Latency
+# TYPE latency_seconds histogram
+latency_seconds_bucket{path="/a\"b",le="0.1"} 1
+latency_seconds_bucket{path="/a\"b",le="0.5"} 2
+latency_seconds_bucket{path="/a\"b",le="+Inf"} 2
+latency_seconds_sum{path="/a\"b"} 0.35
+latency_seconds_count{path="/a\"b"} 2
+# HELP requests_total Total requests
+# TYPE requests_total counter
+requests_total{method="GET",status="200"} 2
+requests_total{method="POST",status="500"} 3
+`, buf.String())
+}
+
+func TestRegistryLabelLimit(t *testing.T) {
+	r := NewRegistry()
+	c := r.WithLabelLimit(2).Counter("hits_total", "", "path")
+	// parent registry is not affected
+	r.Counter("other_total", "", "path").Inc("/a")
+	c.Inc("/a")
+	c.Inc("/b")
+	c.Inc("/c")
+	c.Inc("/d")
+	c.Inc("/a")
+
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `# TYPE hits_total counter
+hits_total{path="/a"} 2
+hits_total{path="/b"} 1
+hits_total{path="__overflow__"} 2
+# TYPE other_total counter
+other_total{path="/a"} 1
+`, buf.String())
+	assert.Equal(t, 0, r.families["other_total"].maxSets)
+}
+
+func TestRegistryKindMismatch(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("x", "")
+	assert.Panics(t, func() {
+		r.Gauge("x", "")
+	})
+}
+
+func TestMethodLabel(t *testing.T) {
+	assert.Equal(t, http.MethodGet, MethodLabel(""))
+	assert.Equal(t, http.MethodPatch, MethodLabel(http.MethodPatch))
+	assert.Equal(t, OtherMethod, MethodLabel("PURGE"))
+}
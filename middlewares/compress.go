@@ -0,0 +1,281 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rkosegi/go-http-commons/config"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// negotiateEncoding selects gzip or deflate according to value of Accept-Encoding header.
+// Empty string is returned if none of them is acceptable.
+func negotiateEncoding(accept string) string {
+	var (
+		best  string
+		bestQ float64
+		qs    = map[string]float64{}
+	)
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) == 0 {
+			continue
+		}
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				continue
+			}
+		}
+		qs[name] = q
+	}
+	// gzip is preferred when quality values are equal
+	for _, enc := range []string{encodingGzip, encodingDeflate} {
+		q, ok := qs[enc]
+		if !ok {
+			q = qs["*"]
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type compressImpl struct {
+	minSize int
+	level   int
+	types   []string
+}
+
+func (c *compressImpl) isCompressible(ct string) bool {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.types {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if typ, _, _ := strings.Cut(mt, "/"); typ == prefix {
+				return true
+			}
+		} else if t == mt {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compressImpl) newCompressor(enc string, w io.Writer) compressor {
+	if enc == encodingGzip {
+		// level is validated by ServerConfig.Check
+		gw, _ := gzip.NewWriterLevel(w, c.level)
+		return gw
+	}
+	fw, _ := flate.NewWriter(w, c.level)
+	return fw
+}
+
+// compressWriter buffers beginning of response until it can decide whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	c       *compressImpl
+	enc     string
+	buf     []byte
+	status  int
+	decided bool
+	cw      compressor
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided || w.status != 0 {
+		return
+	}
+	// informational responses are passed through
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+	// there will be no body to compress
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.c.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.cw != nil {
+		return w.cw.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// decide sends response header and buffered data, compressing them if allowed
+func (w *compressWriter) decide(allowed bool) error {
+	w.decided = true
+	h := w.Header()
+	if len(h.Get("Content-Type")) == 0 && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	// ranges refer to bytes of uncompressed representation
+	if w.status == http.StatusPartialContent || len(h.Get("Content-Range")) > 0 {
+		allowed = false
+	}
+	if allowed && len(h.Get("Content-Encoding")) == 0 && w.c.isCompressible(h.Get("Content-Type")) {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", w.enc)
+		// compressed body is different representation, so it is no longer byte-for-byte identical
+		// to the one that strong validator was computed from
+		if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			h.Set("ETag", "W/"+etag)
+		}
+		w.cw = w.c.newCompressor(w.enc, w.ResponseWriter)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	var err error
+	if len(w.buf) > 0 {
+		if w.cw != nil {
+			_, err = w.cw.Write(w.buf)
+		} else {
+			_, err = w.ResponseWriter.Write(w.buf)
+		}
+	}
+	w.buf = nil
+	return err
+}
+
+// close finishes response once handler returns
+func (w *compressWriter) close() error {
+	if !w.decided {
+		// nothing was written at all, let net/http do its job
+		if w.status == 0 && len(w.buf) == 0 {
+			return nil
+		}
+		return w.decide(len(w.buf) >= w.c.minSize)
+	}
+	if w.cw != nil {
+		return w.cw.Close()
+	}
+	return nil
+}
+
+// Flush sends buffered data to client. Caller that flushes is streaming,
+// so response is compressed regardless of minimal size.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 && len(w.buf) == 0 {
+			// nothing to decide upon yet
+			return
+		}
+		_ = w.decide(true)
+	}
+	if w.cw != nil {
+		_ = w.cw.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack not supported")
+	}
+	// connection is taken over, nothing will be written through this writer anymore
+	w.decided = true
+	return h.Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewCompressionMiddleware creates middleware that compresses responses using gzip or deflate,
+// depending on Accept-Encoding header of request.
+// Only responses of configured content types that are at least cfg.MinSize bytes long are compressed.
+// If compression is not enabled, middleware is no-op.
+func NewCompressionMiddleware(cfg *config.CompressionConfig) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	c := &compressImpl{
+		minSize: cfg.MinSize,
+		level:   cfg.Level,
+		types:   cfg.ContentTypes,
+	}
+	if c.minSize == 0 {
+		c.minSize = config.DefaultCompressionMinSize
+	}
+	if c.level == 0 {
+		c.level = flate.DefaultCompression
+	}
+	if len(c.types) == 0 {
+		c.types = config.DefaultCompressionContentTypes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			enc := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if len(enc) == 0 || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, c: c, enc: enc}
+			next.ServeHTTP(cw, r)
+			_ = cw.close()
+		})
+	}
+}
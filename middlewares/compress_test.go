@@ -0,0 +1,140 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	assert.Equal(t, "gzip", negotiateEncoding("gzip, deflate, br"))
+	assert.Equal(t, "deflate", negotiateEncoding("gzip;q=0.5, deflate"))
+	assert.Equal(t, "deflate", negotiateEncoding("gzip;q=0, *"))
+	assert.Equal(t, "gzip", negotiateEncoding("*"))
+	assert.Equal(t, "", negotiateEncoding("br, identity"))
+	assert.Equal(t, "", negotiateEncoding(""))
+}
+
+func TestCompression(t *testing.T) {
+	big := strings.Repeat("hello compression ", 100)
+	mw := NewCompressionMiddleware(&config.CompressionConfig{Enabled: true, MinSize: 100})
+	serve := func(ae string, h http.HandlerFunc) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", ae)
+		mw(h).ServeHTTP(rr, req)
+		return rr
+	}
+	text := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Length", "123")
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		rr := serve("gzip", text(big))
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+		assert.Empty(t, rr.Header().Get("Content-Length"))
+		gr, err := gzip.NewReader(rr.Body)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(gr)
+		assert.Equal(t, big, string(data))
+	})
+	t.Run("deflate", func(t *testing.T) {
+		rr := serve("deflate", text(big))
+		assert.Equal(t, "deflate", rr.Header().Get("Content-Encoding"))
+		data, _ := io.ReadAll(flate.NewReader(rr.Body))
+		assert.Equal(t, big, string(data))
+	})
+	t.Run("small body", func(t *testing.T) {
+		rr := serve("gzip", text("small"))
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "small", rr.Body.String())
+	})
+	t.Run("not acceptable", func(t *testing.T) {
+		rr := serve("br", text(big))
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+		assert.Equal(t, big, rr.Body.String())
+	})
+	t.Run("already compressed type", func(t *testing.T) {
+		rr := serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(big))
+		})
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, big, rr.Body.String())
+	})
+	t.Run("flush", func(t *testing.T) {
+		rr := serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: 1\n\n"))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("data: 2\n\n"))
+		})
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		assert.True(t, rr.Flushed)
+		gr, err := gzip.NewReader(rr.Body)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(gr)
+		assert.Equal(t, "data: 1\n\ndata: 2\n\n", string(data))
+	})
+	t.Run("etag", func(t *testing.T) {
+		rr := serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"abc"`)
+			text(big)(w, r)
+		})
+		assert.Equal(t, `W/"abc"`, rr.Header().Get("ETag"))
+		rr = serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"abc"`)
+			text("small")(w, r)
+		})
+		assert.Equal(t, `"abc"`, rr.Header().Get("ETag"))
+	})
+	t.Run("partial content", func(t *testing.T) {
+		rr := serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Range", "bytes 0-1799/5000")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(big))
+		})
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, big, rr.Body.String())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		NewCompressionMiddleware(&config.CompressionConfig{})(text(big)).ServeHTTP(rr, req)
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	})
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/rkosegi/go-http-commons/metrics"
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+const (
+	MetricConcurrencyInFlight = "http_concurrency_in_flight"
+	MetricConcurrencyLimit    = "http_concurrency_limit"
+	MetricConcurrencyQueued   = "http_concurrency_queued"
+	MetricConcurrencyShed     = "http_concurrency_shed_total"
+
+	// GlobalScope is value of scope label of metrics related to global limit
+	GlobalScope = "global"
+
+	DefaultConcurrencyLimit = 100
+	DefaultShedRetryAfter   = time.Second
+	// DefaultAdaptiveBackoff is factor that adaptive limit is multiplied by when latency exceeds target
+	DefaultAdaptiveBackoff = 0.9
+)
+
+// ErrOverloaded is sent with status 503 when request is shed
+var ErrOverloaded = errors.New("server is overloaded, try again later")
+
+type waiter struct {
+	prio  int
+	seq   uint64
+	ready chan struct{}
+	index int
+}
+
+// waitQueue is priority queue of waiters, higher priority first, FIFO within the same priority
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].prio != q[j].prio {
+		return q[i].prio > q[j].prio
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	w.index = -1
+	return w
+}
+
+type adaptiveCfg struct {
+	min, max int
+	target   time.Duration
+}
+
+// semaphore limits number of concurrent requests, optionally adjusting limit from observed latency
+type semaphore struct {
+	scope    string
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+	queue    waitQueue
+	maxQueue int
+	seq      uint64
+	adaptive *adaptiveCfg
+	m        *concurrencyMetrics
+	// lastDecrease is time of last multiplicative decrease of adaptive limit
+	lastDecrease time.Time
+}
+
+func (s *semaphore) report() {
+	s.m.inFlight.Set(float64(s.inflight), s.scope)
+	s.m.limit.Set(float64(int(s.limit)), s.scope)
+	s.m.queued.Set(float64(s.queue.Len()), s.scope)
+}
+
+// acquire obtains slot, waiting in queue until deadline if there is none available
+func (s *semaphore) acquire(ctx context.Context, prio int, deadline time.Time) bool {
+	s.mu.Lock()
+	if s.inflight < int(s.limit) && s.queue.Len() == 0 {
+		s.inflight++
+		s.report()
+		s.mu.Unlock()
+		return true
+	}
+	wait := time.Until(deadline)
+	if s.queue.Len() >= s.maxQueue || wait <= 0 {
+		s.m.shed.Inc(s.scope)
+		s.mu.Unlock()
+		return false
+	}
+	s.seq++
+	w := &waiter{prio: prio, seq: s.seq, ready: make(chan struct{})}
+	heap.Push(&s.queue, w)
+	s.report()
+	s.mu.Unlock()
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-w.ready:
+		return true
+	case <-t.C:
+	case <-ctx.Done():
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w.index < 0 {
+		// slot was granted in the meantime
+		return true
+	}
+	heap.Remove(&s.queue, w.index)
+	s.m.shed.Inc(s.scope)
+	s.report()
+	return false
+}
+
+// release returns slot, start time and latency of request are used to adjust limit in adaptive mode
+func (s *semaphore) release(start time.Time, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saturated := s.inflight >= int(s.limit)
+	s.inflight--
+	if a := s.adaptive; a != nil {
+		if latency > a.target {
+			// multiplicative decrease, at most once per round trip: requests that were already
+			// in flight when limit was decreased reflect load before decrease, so they are ignored
+			if start.After(s.lastDecrease) {
+				s.limit = max(float64(a.min), s.limit*DefaultAdaptiveBackoff)
+				s.lastDecrease = start.Add(latency)
+			}
+		} else if saturated {
+			// additive increase, roughly by one per limit's worth of requests
+			s.limit = min(float64(a.max), s.limit+1/s.limit)
+		}
+	}
+	for s.inflight < int(s.limit) && s.queue.Len() > 0 {
+		w := heap.Pop(&s.queue).(*waiter)
+		s.inflight++
+		close(w.ready)
+	}
+	s.report()
+}
+
+type concurrencyMetrics struct {
+	inFlight *metrics.GaugeVec
+	limit    *metrics.GaugeVec
+	queued   *metrics.GaugeVec
+	shed     *metrics.CounterVec
+}
+
+func newConcurrencyMetrics(reg *metrics.Registry) *concurrencyMetrics {
+	return &concurrencyMetrics{
+		inFlight: reg.Gauge(MetricConcurrencyInFlight, "Number of requests currently admitted by concurrency limiter", "scope"),
+		limit:    reg.Gauge(MetricConcurrencyLimit, "Current limit of concurrent requests", "scope"),
+		queued:   reg.Gauge(MetricConcurrencyQueued, "Number of requests waiting for admission", "scope"),
+		shed:     reg.Counter(MetricConcurrencyShed, "Total number of requests rejected by concurrency limiter", "scope"),
+	}
+}
+
+// ConcurrencyLimitBuilder is interface to support building of middleware that caps number of requests
+// processed at once and sheds load once limit is reached.
+type ConcurrencyLimitBuilder interface {
+	// WithLimit sets global limit of in-flight requests. By default, DefaultConcurrencyLimit is used.
+	WithLimit(n int) ConcurrencyLimitBuilder
+
+	// WithRouteLimit sets limit of in-flight requests that match pattern, using syntax of http.ServeMux.
+	// Requests that match route are subject to global limit as well.
+	WithRouteLimit(pattern string, n int) ConcurrencyLimitBuilder
+
+	// WithQueue allows up to size requests to wait for at most maxWait when limit is reached.
+	// By default, requests are shed immediately.
+	WithQueue(size int, maxWait time.Duration) ConcurrencyLimitBuilder
+
+	// WithPriorityFn sets function that assigns priority to request. Queued requests with higher priority
+	// are admitted first.
+	WithPriorityFn(fn func(r *http.Request) int) ConcurrencyLimitBuilder
+
+	// WithAdaptiveLimit enables adjustment of global limit, starting from value set by WithLimit.
+	// Limit is increased additively while latency stays below target and is saturated,
+	// and decreased multiplicatively once latency exceeds target, staying within [minLimit, maxLimit].
+	// Limit is decreased at most once per round trip, slow requests that started before last decrease
+	// don't decrease it again.
+	WithAdaptiveLimit(minLimit, maxLimit int, target time.Duration) ConcurrencyLimitBuilder
+
+	// WithRetryAfter sets value of Retry-After header sent with shed requests.
+	// By default, DefaultShedRetryAfter is used.
+	WithRetryAfter(d time.Duration) ConcurrencyLimitBuilder
+
+	// WithRegistry sets metrics.Registry where metrics are recorded.
+	// By default, metrics.DefaultRegistry is used.
+	WithRegistry(reg *metrics.Registry) ConcurrencyLimitBuilder
+
+	// WithName sets name of limiter, which is used as value of scope label of global limit metrics
+	// and as prefix of scope label of route limit metrics.
+	// Every limiter that shares registry with another one should have unique name.
+	// By default, GlobalScope is used for global limit and pattern alone for routes.
+	WithName(name string) ConcurrencyLimitBuilder
+
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) ConcurrencyLimitBuilder
+
+	// Check returns error if any route pattern is invalid or conflicts with another one.
+	Check() error
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	// It panics if route patterns don't pass Check.
+	Build() func(http.Handler) http.Handler
+}
+
+type routeLimit struct {
+	pattern string
+	limit   int
+}
+
+type concurrencyBuilderImpl struct {
+	limit      int
+	routes     []routeLimit
+	queueSize  int
+	maxWait    time.Duration
+	prioFn     func(r *http.Request) int
+	adaptive   *adaptiveCfg
+	retryAfter time.Duration
+	reg        *metrics.Registry
+	out        output.Interface
+	name       string
+}
+
+func (c *concurrencyBuilderImpl) WithLimit(n int) ConcurrencyLimitBuilder {
+	c.limit = n
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithRouteLimit(pattern string, n int) ConcurrencyLimitBuilder {
+	c.routes = append(c.routes, routeLimit{pattern: pattern, limit: n})
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithQueue(size int, maxWait time.Duration) ConcurrencyLimitBuilder {
+	c.queueSize = size
+	c.maxWait = maxWait
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithPriorityFn(fn func(r *http.Request) int) ConcurrencyLimitBuilder {
+	c.prioFn = fn
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithAdaptiveLimit(minLimit, maxLimit int, target time.Duration) ConcurrencyLimitBuilder {
+	c.adaptive = &adaptiveCfg{min: max(minLimit, 1), max: maxLimit, target: target}
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithRetryAfter(d time.Duration) ConcurrencyLimitBuilder {
+	c.retryAfter = d
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithRegistry(reg *metrics.Registry) ConcurrencyLimitBuilder {
+	c.reg = reg
+	return c
+}
+
+func (c *concurrencyBuilderImpl) WithName(name string) ConcurrencyLimitBuilder {
+	c.name = name
+	return c
+}
+
+func (c *concurrencyBuilderImpl) Check() error {
+	patterns := make([]string, len(c.routes))
+	for i, rl := range c.routes {
+		patterns[i] = rl.pattern
+	}
+	return config.CheckRoutePatterns(patterns...)
+}
+
+func (c *concurrencyBuilderImpl) WithOutput(out output.Interface) ConcurrencyLimitBuilder {
+	c.out = out
+	return c
+}
+
+func (c *concurrencyBuilderImpl) newSemaphore(scope string, limit int, m *concurrencyMetrics) *semaphore {
+	s := &semaphore{scope: scope, limit: float64(limit), maxQueue: c.queueSize, m: m}
+	s.report()
+	return s
+}
+
+func (c *concurrencyBuilderImpl) Build() func(http.Handler) http.Handler {
+	if err := c.Check(); err != nil {
+		panic(err)
+	}
+	m := newConcurrencyMetrics(c.reg)
+	globalScope, routePrefix := GlobalScope, ""
+	if len(c.name) > 0 {
+		globalScope, routePrefix = c.name, c.name+":"
+	}
+	var (
+		out        = c.out
+		prioFn     = c.prioFn
+		maxWait    = c.maxWait
+		retryAfter = ceilSeconds(c.retryAfter)
+		global     = c.newSemaphore(globalScope, c.limit, m)
+		mux        = http.NewServeMux()
+		routes     = make([]*semaphore, len(c.routes))
+	)
+	if c.adaptive != nil {
+		a := *c.adaptive
+		a.max = max(a.max, a.min)
+		global.adaptive = &a
+		global.limit = min(max(global.limit, float64(a.min)), float64(a.max))
+		global.report()
+	}
+	for i, rl := range c.routes {
+		routes[i] = c.newSemaphore(routePrefix+rl.pattern, rl.limit, m)
+		mux.Handle(rl.pattern, routeMarker(i))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				prio     int
+				route    *semaphore
+				ctx      = r.Context()
+				deadline = time.Now().Add(maxWait)
+			)
+			if prioFn != nil {
+				prio = prioFn(r)
+			}
+			if len(routes) > 0 {
+				if h, _ := mux.Handler(r); h != nil {
+					if idx, ok := h.(routeMarker); ok {
+						route = routes[idx]
+					}
+				}
+			}
+			shed := func() {
+				w.Header().Set("Retry-After", retryAfter)
+				out.SendWithStatus(w, output.WrapError(ErrOverloaded, http.StatusServiceUnavailable),
+					http.StatusServiceUnavailable)
+			}
+			if route != nil {
+				if !route.acquire(ctx, prio, deadline) {
+					shed()
+					return
+				}
+			}
+			if !global.acquire(ctx, prio, deadline) {
+				if route != nil {
+					route.release(time.Time{}, 0)
+				}
+				shed()
+				return
+			}
+			start := time.Now()
+			defer func() {
+				latency := time.Since(start)
+				global.release(start, latency)
+				if route != nil {
+					route.release(start, latency)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewConcurrencyLimitBuilder creates ConcurrencyLimitBuilder with defaults.
+func NewConcurrencyLimitBuilder() ConcurrencyLimitBuilder {
+	return &concurrencyBuilderImpl{
+		limit:      DefaultConcurrencyLimit,
+		retryAfter: DefaultShedRetryAfter,
+		reg:        metrics.DefaultRegistry(),
+		out:        output.DefaultOutput(),
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimitShed(t *testing.T) {
+	reg := metrics.NewRegistry()
+	var (
+		entered = make(chan struct{})
+		unblock = make(chan struct{})
+	)
+	h := NewConcurrencyLimitBuilder().WithLimit(1).WithRegistry(reg).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-unblock
+	}))
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		close(done)
+	}()
+	<-entered
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	close(unblock)
+	<-done
+
+	var buf bytes.Buffer
+	_, _ = reg.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `http_concurrency_shed_total{scope="global"} 1`)
+	assert.Contains(t, buf.String(), `http_concurrency_in_flight{scope="global"} 0`)
+}
+
+func TestConcurrencyLimitQueuePriority(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		order   []int
+		entered = make(chan struct{}, 3)
+		unblock = make(chan struct{})
+	)
+	mw := NewConcurrencyLimitBuilder().
+		WithLimit(5).
+		WithRouteLimit("/slow", 1).
+		WithQueue(2, time.Second).
+		WithRegistry(metrics.NewRegistry()).
+		WithPriorityFn(func(r *http.Request) int {
+			p, _ := strconv.Atoi(r.URL.Query().Get("p"))
+			return p
+		}).Build()
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := strconv.Atoi(r.URL.Query().Get("p"))
+		mu.Lock()
+		order = append(order, p)
+		mu.Unlock()
+		entered <- struct{}{}
+		if p == 0 {
+			<-unblock
+		}
+	}))
+	var wg sync.WaitGroup
+	serve := func(p int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow?p="+strconv.Itoa(p), nil))
+		}()
+	}
+	serve(0)
+	<-entered
+	serve(1)
+	time.Sleep(20 * time.Millisecond)
+	serve(5)
+	time.Sleep(20 * time.Millisecond)
+	// queue is full
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/slow?p=9", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	// other routes are not affected
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/fast?p=2", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	<-entered
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, []int{0, 2, 5, 1}, order)
+}
+
+func TestConcurrencyLimitQueueTimeout(t *testing.T) {
+	s := &semaphore{scope: "test", limit: 1, maxQueue: 1, m: newConcurrencyMetrics(metrics.NewRegistry())}
+	assert.True(t, s.acquire(context.Background(), 0, time.Now()))
+	assert.False(t, s.acquire(context.Background(), 0, time.Now().Add(10*time.Millisecond)))
+	assert.Equal(t, 0, s.queue.Len())
+	s.release(time.Now(), 0)
+	assert.Equal(t, 0, s.inflight)
+}
+
+func TestAdaptiveLimit(t *testing.T) {
+	s := &semaphore{scope: "test", limit: 10, maxQueue: 0, adaptive: &adaptiveCfg{min: 2, max: 11, target: 10 * time.Millisecond},
+		m: newConcurrencyMetrics(metrics.NewRegistry())}
+	ctx := context.Background()
+	now := time.Now()
+	assert.True(t, s.acquire(ctx, 0, now))
+	s.release(now, 50*time.Millisecond)
+	assert.InDelta(t, 9, s.limit, 0.001)
+	// not saturated, limit stays
+	now = now.Add(time.Second)
+	assert.True(t, s.acquire(ctx, 0, now))
+	s.release(now, time.Millisecond)
+	assert.InDelta(t, 9, s.limit, 0.001)
+	// saturated and fast, limit grows up to max
+	for range 100 {
+		for range 11 {
+			s.acquire(ctx, 0, now)
+		}
+		for s.inflight > 0 {
+			s.release(now, time.Millisecond)
+		}
+	}
+	assert.InDelta(t, 11, s.limit, 0.001)
+	// burst of slow requests that were in flight at once decreases limit just once
+	for range 11 {
+		s.acquire(ctx, 0, now)
+	}
+	for s.inflight > 0 {
+		s.release(now, time.Second)
+	}
+	assert.InDelta(t, 9.9, s.limit, 0.001)
+	for range 100 {
+		now = now.Add(2 * time.Second)
+		s.acquire(ctx, 0, now)
+		s.release(now, time.Second)
+	}
+	assert.InDelta(t, 2, s.limit, 0.001)
+}
+
+func TestConcurrencyLimitRoutesAndName(t *testing.T) {
+	b := NewConcurrencyLimitBuilder().WithRouteLimit("/a/", 1).WithRouteLimit("/a/", 2)
+	assert.Error(t, b.Check())
+	assert.Panics(t, func() { b.Build() })
+
+	reg := metrics.NewRegistry()
+	NewConcurrencyLimitBuilder().WithRegistry(reg).Build()
+	NewConcurrencyLimitBuilder().WithRegistry(reg).WithName("admin").WithRouteLimit("/a/", 1).Build()
+	var buf bytes.Buffer
+	_, _ = reg.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `http_concurrency_limit{scope="global"} 100`)
+	assert.Contains(t, buf.String(), `http_concurrency_limit{scope="admin"} 100`)
+	assert.Contains(t, buf.String(), `http_concurrency_limit{scope="admin:/a/"} 1`)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rkosegi/go-http-commons/config"
+)
+
+var DefaultCorsAllowedMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+type originMatcher func(origin string) bool
+
+type corsImpl struct {
+	anyOrigin      bool
+	origins        []originMatcher
+	methods        []string
+	anyHeader      bool
+	headers        []string
+	exposedHeaders string
+	credentials    bool
+	maxAge         string
+}
+
+// newOriginMatcher creates matcher for single origin pattern.
+// Pattern can contain at most one '*', which matches any non-empty sequence of characters,
+// such as "https://*.example.com".
+func newOriginMatcher(pattern string) originMatcher {
+	pattern = strings.ToLower(pattern)
+	if prefix, suffix, found := strings.Cut(pattern, "*"); found {
+		return func(origin string) bool {
+			return len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+		}
+	}
+	return func(origin string) bool {
+		return origin == pattern
+	}
+}
+
+func (c *corsImpl) isOriginAllowed(origin string) bool {
+	if c.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, m := range c.origins {
+		if m(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *corsImpl) isMethodAllowed(method string) bool {
+	// simple methods are always allowed
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodPost {
+		return true
+	}
+	for _, m := range c.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *corsImpl) areHeadersAllowed(hdrs []string) bool {
+	if c.anyHeader {
+		return true
+	}
+outer:
+	for _, h := range hdrs {
+		for _, a := range c.headers {
+			if strings.EqualFold(a, h) {
+				continue outer
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (c *corsImpl) setAllowOrigin(h http.Header, origin string) {
+	// wildcard can't be used together with credentials, so echo origin back instead
+	if c.anyOrigin && !c.credentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if c.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *corsImpl) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	origin := r.Header.Get("Origin")
+	reqMethod := r.Header.Get("Access-Control-Request-Method")
+	reqHeaders := parseHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+	if c.isOriginAllowed(origin) && c.isMethodAllowed(reqMethod) && c.areHeadersAllowed(reqHeaders) {
+		c.setAllowOrigin(h, origin)
+		h.Set("Access-Control-Allow-Methods", strings.Join(c.methods, ", "))
+		if len(reqHeaders) > 0 {
+			if c.anyHeader {
+				h.Set("Access-Control-Allow-Headers", strings.Join(reqHeaders, ", "))
+			} else {
+				h.Set("Access-Control-Allow-Headers", strings.Join(c.headers, ", "))
+			}
+		}
+		if len(c.maxAge) > 0 {
+			h.Set("Access-Control-Max-Age", c.maxAge)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *corsImpl) handleActual(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if c.isOriginAllowed(origin) {
+		c.setAllowOrigin(h, origin)
+		if len(c.exposedHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", c.exposedHeaders)
+		}
+	}
+}
+
+func parseHeaderList(v string) []string {
+	var out []string
+	for _, h := range strings.Split(v, ",") {
+		if h = strings.TrimSpace(h); len(h) > 0 {
+			out = append(out, http.CanonicalHeaderKey(h))
+		}
+	}
+	return out
+}
+
+// NewCorsMiddleware creates middleware that handles CORS requests according to provided configuration.
+// Preflight requests are answered directly, without invoking next handler.
+// If AllowedMethods is empty, DefaultCorsAllowedMethods is used instead.
+func NewCorsMiddleware(cfg *config.CorsConfig) func(http.Handler) http.Handler {
+	c := &corsImpl{
+		methods:     DefaultCorsAllowedMethods,
+		credentials: cfg.AllowCredentials,
+	}
+	for _, o := range cfg.AllowedOrigins {
+		if o == "*" {
+			c.anyOrigin = true
+		} else {
+			c.origins = append(c.origins, newOriginMatcher(o))
+		}
+	}
+	if len(cfg.AllowedMethods) > 0 {
+		c.methods = make([]string, 0, len(cfg.AllowedMethods))
+		for _, m := range cfg.AllowedMethods {
+			c.methods = append(c.methods, strings.ToUpper(m))
+		}
+	}
+	for _, h := range cfg.AllowedHeaders {
+		if h == "*" {
+			c.anyHeader = true
+		} else {
+			c.headers = append(c.headers, http.CanonicalHeaderKey(h))
+		}
+	}
+	if len(cfg.ExposedHeaders) > 0 {
+		c.exposedHeaders = strings.Join(cfg.ExposedHeaders, ", ")
+	}
+	if cfg.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.Header.Get("Origin")) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+				c.handlePreflight(w, r)
+				return
+			}
+			c.handleActual(w, r)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+// DefaultMaxDecompressedBytes is default limit of decompressed request body size
+const DefaultMaxDecompressedBytes = 10 << 20
+
+var (
+	// ErrUnsupportedEncoding is sent with status 415 when request body uses unknown Content-Encoding
+	ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+	// ErrMalformedEncoding is sent with status 400 when request body can't be decompressed
+	ErrMalformedEncoding = errors.New("malformed compressed request body")
+)
+
+// DecompressionBuilder is interface to support building of middleware that transparently
+// decompresses request bodies sent with Content-Encoding gzip or deflate.
+type DecompressionBuilder interface {
+	// WithMaxBytes sets limit of decompressed body size.
+	// Reading past this limit fails with *http.MaxBytesError, which body.ConsumeAs reports with status 413.
+	// Zero or negative value means no limit. By default, DefaultMaxDecompressedBytes is used.
+	WithMaxBytes(n int64) DecompressionBuilder
+
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) DecompressionBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type decompressionBuilderImpl struct {
+	maxBytes int64
+	out      output.Interface
+}
+
+func (d *decompressionBuilderImpl) WithMaxBytes(n int64) DecompressionBuilder {
+	d.maxBytes = n
+	return d
+}
+
+func (d *decompressionBuilderImpl) WithOutput(out output.Interface) DecompressionBuilder {
+	d.out = out
+	return d
+}
+
+// decompressedBody closes both decompressor and original body
+type decompressedBody struct {
+	io.Reader
+	dec  io.Closer
+	orig io.Closer
+}
+
+// Read reports corrupted stream as ErrMalformedEncoding, so that it is sent with status 400
+func (d *decompressedBody) Read(p []byte) (int, error) {
+	n, err := d.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		err = output.WrapError(errors.Join(ErrMalformedEncoding, err), http.StatusBadRequest)
+	}
+	return n, err
+}
+
+func (d *decompressedBody) Close() error {
+	return errors.Join(d.dec.Close(), d.orig.Close())
+}
+
+// newDeflateReader handles both zlib-wrapped stream mandated by RFC 9110 and raw deflate stream
+// that some clients send instead.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	hdr, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// zlib header: CM=8 in low nibble of CMF and header checksum
+	if hdr[0]&0x0f == 8 && (uint16(hdr[0])<<8|uint16(hdr[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+func (d *decompressionBuilderImpl) Build() func(http.Handler) http.Handler {
+	var (
+		maxBytes = d.maxBytes
+		out      = d.out
+	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			enc := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			var (
+				dec io.ReadCloser
+				err error
+			)
+			switch enc {
+			case "", "identity":
+				next.ServeHTTP(w, r)
+				return
+			case "gzip", "x-gzip":
+				dec, err = gzip.NewReader(r.Body)
+			case "deflate":
+				dec, err = newDeflateReader(r.Body)
+			default:
+				w.Header().Set("Accept-Encoding", "gzip, deflate")
+				out.SendWithStatus(w, output.WrapError(fmt.Errorf("%w: %s", ErrUnsupportedEncoding, enc),
+					http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+				return
+			}
+			if err != nil {
+				out.SendWithStatus(w, output.WrapError(errors.Join(ErrMalformedEncoding, err),
+					http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			r.Body = &decompressedBody{Reader: dec, dec: dec, orig: r.Body}
+			if maxBytes > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewDecompressionBuilder creates DecompressionBuilder with the defaults.
+func NewDecompressionBuilder() DecompressionBuilder {
+	return &decompressionBuilderImpl{
+		maxBytes: DefaultMaxDecompressedBytes,
+		out:      output.DefaultOutput(),
+	}
+}
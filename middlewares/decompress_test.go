@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/body"
+	"github.com/rkosegi/go-http-commons/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompression(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+	compress := func(enc string, data string) *bytes.Buffer {
+		var (
+			buf bytes.Buffer
+			w   io.WriteCloser
+		)
+		switch enc {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		default:
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		}
+		_, _ = w.Write([]byte(data))
+		_ = w.Close()
+		return &buf
+	}
+	out := output.NewBuilder().Build()
+	h := NewDecompressionBuilder().WithMaxBytes(64).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Content-Encoding"))
+		p, err := body.ConsumeAs[payload](r)
+		if err != nil {
+			out.SendWithStatus(w, err, http.StatusInternalServerError)
+			return
+		}
+		out.SendWithStatus(w, p, http.StatusOK)
+	}))
+	serve := func(enc string, b io.Reader) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", b)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", enc)
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for _, enc := range []string{"gzip", "deflate", "raw"} {
+		t.Run(enc, func(t *testing.T) {
+			hdr := enc
+			if enc == "raw" {
+				hdr = "deflate"
+			}
+			rr := serve(hdr, compress(enc, `{"name":"abc"}`))
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"abc"`)
+		})
+	}
+	t.Run("identity", func(t *testing.T) {
+		rr := serve("", strings.NewReader(`{"name":"abc"}`))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+	t.Run("zip bomb", func(t *testing.T) {
+		rr := serve("gzip", compress("gzip", `{"name":"`+strings.Repeat("a", 1000)+`"}`))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+	t.Run("unknown encoding", func(t *testing.T) {
+		rr := serve("br", strings.NewReader("whatever"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+		assert.Equal(t, "gzip, deflate", rr.Header().Get("Accept-Encoding"))
+	})
+	t.Run("malformed", func(t *testing.T) {
+		rr := serve("gzip", strings.NewReader("not gzip at all"))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+	t.Run("corrupted stream", func(t *testing.T) {
+		data := compress("gzip", `{"name":"abc"}`).Bytes()
+		// valid header, but stream ends in the middle of compressed data
+		rr := serve("gzip", bytes.NewReader(data[:len(data)-12]))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), ErrMalformedEncoding.Error())
+	})
+	t.Run("unlimited", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", compress("gzip", strings.Repeat("a", 1000)))
+		req.Header.Set("Content-Encoding", "gzip")
+		NewDecompressionBuilder().WithMaxBytes(0).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Len(t, data, 1000)
+		})).ServeHTTP(rr, req)
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"time"
 )
 
 // InterceptedResponse gives access to HTTP response details sent back to client
@@ -36,6 +37,20 @@ type InterceptedResponse interface {
 	Request() *http.Request
 }
 
+// TimedResponse is InterceptedResponse that also carries timing of request processing.
+// InterceptedResponse passed to callbacks by this package implements it, use type assertion to access it.
+type TimedResponse interface {
+	InterceptedResponse
+	// StartTime returns time when request processing started
+	StartTime() time.Time
+	// TTFB returns time to first byte, that is duration between start of request processing
+	// and moment when response header or first byte of body was written.
+	// Zero is returned if nothing was written.
+	TTFB() time.Duration
+	// Duration returns total duration of request processing
+	Duration() time.Duration
+}
+
 type respInterceptor struct {
 	wroteHeader bool
 	delegate    http.ResponseWriter
@@ -43,6 +58,24 @@ type respInterceptor struct {
 	status      int
 	req         *http.Request
 	sendStatus  sync.Once
+	start       time.Time
+	firstByte   time.Time
+	end         time.Time
+}
+
+func newRespInterceptor(w http.ResponseWriter, r *http.Request) *respInterceptor {
+	return &respInterceptor{delegate: w, req: r, start: time.Now()}
+}
+
+func (i *respInterceptor) markFirstByte() {
+	if i.firstByte.IsZero() {
+		i.firstByte = time.Now()
+	}
+}
+
+// done marks end of request processing
+func (i *respInterceptor) done() {
+	i.end = time.Now()
 }
 
 func (i *respInterceptor) Header() http.Header {
@@ -51,6 +84,7 @@ func (i *respInterceptor) Header() http.Header {
 
 func (i *respInterceptor) Write(bytes []byte) (int, error) {
 	i.wroteHeader = true
+	i.markFirstByte()
 	size, err := i.delegate.Write(bytes)
 	i.written += size
 	return size, err
@@ -58,11 +92,19 @@ func (i *respInterceptor) Write(bytes []byte) (int, error) {
 
 func (i *respInterceptor) WriteHeader(statusCode int) {
 	if !i.wroteHeader {
+		i.markFirstByte()
 		i.delegate.WriteHeader(statusCode)
 		i.status = statusCode
+		// informational responses are followed by the final one
+		i.wroteHeader = statusCode >= http.StatusOK
 	}
 }
 
+// WroteHeader returns true if response header was already sent to client
+func (i *respInterceptor) WroteHeader() bool {
+	return i.wroteHeader
+}
+
 func (i *respInterceptor) Written() int {
 	return i.written
 }
@@ -77,6 +119,24 @@ func (i *respInterceptor) Request() *http.Request {
 	return i.req
 }
 
+func (i *respInterceptor) StartTime() time.Time {
+	return i.start
+}
+
+func (i *respInterceptor) TTFB() time.Duration {
+	if i.firstByte.IsZero() {
+		return 0
+	}
+	return i.firstByte.Sub(i.start)
+}
+
+func (i *respInterceptor) Duration() time.Duration {
+	if i.end.IsZero() {
+		return time.Since(i.start)
+	}
+	return i.end.Sub(i.start)
+}
+
 func (i *respInterceptor) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	h, ok := i.delegate.(http.Hijacker)
 	if !ok {
@@ -85,6 +145,19 @@ func (i *respInterceptor) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return h.Hijack()
 }
 
+// Flush sends buffered data to client. Flushing commits response header, so it counts as first byte.
+func (i *respInterceptor) Flush() {
+	i.wroteHeader = true
+	i.markFirstByte()
+	if f, ok := i.delegate.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (i *respInterceptor) Unwrap() http.ResponseWriter {
+	return i.delegate
+}
+
 type InterceptorBuilder interface {
 	// WithRequestFilter sets the custom filtering predicate.
 	// If provided function return false, request is processed without interception
@@ -125,8 +198,9 @@ func (i *interceptorBuilderImpl) Build() func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if i.filterFn(r) {
 				i.bcb(r)
-				ir := &respInterceptor{delegate: w, req: r}
+				ir := newRespInterceptor(w, r)
 				next.ServeHTTP(ir, r)
+				ir.done()
 				i.cb(ir)
 			} else {
 				next.ServeHTTP(w, r)
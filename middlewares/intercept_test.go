@@ -0,0 +1,62 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptorTiming(t *testing.T) {
+	var captured InterceptedResponse
+	h := NewInterceptorBuilder().WithCallback(func(resp InterceptedResponse) {
+		captured = resp
+	}).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+		time.Sleep(10 * time.Millisecond)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NotNil(t, captured)
+	assert.Equal(t, http.StatusAccepted, captured.Status())
+	tr, ok := captured.(TimedResponse)
+	assert.True(t, ok)
+	assert.False(t, tr.StartTime().IsZero())
+	assert.GreaterOrEqual(t, tr.TTFB(), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, tr.Duration(), 20*time.Millisecond)
+	assert.Greater(t, tr.Duration(), tr.TTFB())
+	// duration is frozen once request is processed
+	d := tr.Duration()
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, d, tr.Duration())
+
+	// nothing written, so there is no first byte
+	h = NewInterceptorBuilder().WithCallback(func(resp InterceptedResponse) {
+		captured = resp
+	}).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	tr = captured.(TimedResponse)
+	assert.Zero(t, tr.TTFB())
+	assert.Positive(t, tr.Duration())
+}
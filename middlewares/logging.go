@@ -19,6 +19,10 @@ package middlewares
 import (
 	"log/slog"
 	"net/http"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/auth"
+	"github.com/rkosegi/go-http-commons/requestid"
 )
 
 var DefaultLoggingMiddleware = NewLoggingBuilder().Build()
@@ -57,6 +61,25 @@ func SizeReqInfoExtractor() ReqInfoExtractorFn {
 	}
 }
 
+// RequestIDReqInfoExtractor extracts request ID assigned by middleware built using RequestIDBuilder.
+// Logging middleware must be placed after that middleware in chain.
+func RequestIDReqInfoExtractor() ReqInfoExtractorFn {
+	return func(r *http.Request) (string, interface{}) {
+		return requestid.LogKey, requestid.FromContext(r.Context())
+	}
+}
+
+// PrincipalReqInfoExtractor extracts subject of principal authenticated by auth middleware.
+// Logging middleware must be placed after that middleware in chain. Empty string is logged for anonymous requests.
+func PrincipalReqInfoExtractor() ReqInfoExtractorFn {
+	return func(r *http.Request) (string, interface{}) {
+		if p := auth.FromContext(r.Context()); p != nil {
+			return "principal", p.Subject
+		}
+		return "principal", ""
+	}
+}
+
 // DeferredReqInfoExtractor delegates extraction to provided ReqInfoExtractorFn
 func DeferredReqInfoExtractor(delegate ReqInfoExtractorFn) ReqInfoExtractorFn {
 	return func(r *http.Request) (string, interface{}) {
@@ -82,6 +105,28 @@ func SizeRespInfoExtractor() RespInfoExtractorFn {
 	}
 }
 
+// DurationRespInfoExtractor extracts total duration of request processing.
+// Zero is reported if response does not implement TimedResponse.
+func DurationRespInfoExtractor() RespInfoExtractorFn {
+	return func(resp InterceptedResponse) (string, interface{}) {
+		if tr, ok := resp.(TimedResponse); ok {
+			return "duration", tr.Duration()
+		}
+		return "duration", time.Duration(0)
+	}
+}
+
+// TTFBRespInfoExtractor extracts time to first byte of response.
+// Zero is reported if response does not implement TimedResponse.
+func TTFBRespInfoExtractor() RespInfoExtractorFn {
+	return func(resp InterceptedResponse) (string, interface{}) {
+		if tr, ok := resp.(TimedResponse); ok {
+			return "ttfb", tr.TTFB()
+		}
+		return "ttfb", time.Duration(0)
+	}
+}
+
 func HeaderRespInfoExtractor(hdr string) RespInfoExtractorFn {
 	return func(resp InterceptedResponse) (string, interface{}) {
 		return hdr, resp.Header().Get(hdr)
@@ -225,8 +270,9 @@ func (l *loggingBuilderImpl) Build() func(http.Handler) http.Handler {
 				c.l.Log(r.Context(), c.lvl, c.reqMsg, args...)
 			}
 			if c.respLog {
-				ir := &respInterceptor{delegate: w, req: r}
+				ir := newRespInterceptor(w, r)
 				next.ServeHTTP(ir, r)
+				ir.done()
 				var args []any
 				for _, fn := range c.respInfoFns {
 					k, v := fn(ir)
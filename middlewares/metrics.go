@@ -0,0 +1,130 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/rkosegi/go-http-commons/metrics"
+)
+
+const (
+	MetricRequestsTotal    = "http_requests_total"
+	MetricRequestsInFlight = "http_requests_in_flight"
+	MetricRequestDuration  = "http_request_duration_seconds"
+
+	// UnmatchedRoute is route label value used when route can't be determined
+	UnmatchedRoute = "unmatched"
+)
+
+// PatternRouteFn returns pattern of http.ServeMux that matched the request.
+// Note that pattern is only visible if request instance was not replaced by any middleware
+// between this one and the http.ServeMux.
+func PatternRouteFn(r *http.Request) string {
+	if len(r.Pattern) == 0 {
+		return UnmatchedRoute
+	}
+	return r.Pattern
+}
+
+// MetricsBuilder is interface to support building of metrics middleware.
+type MetricsBuilder interface {
+	// WithRegistry sets metrics.Registry where metrics are recorded.
+	// By default, metrics.DefaultRegistry is used.
+	WithRegistry(reg *metrics.Registry) MetricsBuilder
+
+	// WithRouteFn sets function that is used to compute value of route label.
+	// Function is invoked after request was processed. By default, PatternRouteFn is used.
+	// Never use raw URL path here, as it leads to unbounded cardinality.
+	WithRouteFn(fn func(*http.Request) string) MetricsBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type metricsBuilderImpl struct {
+	cfg     *config.TelemetryConfig
+	reg     *metrics.Registry
+	routeFn func(*http.Request) string
+}
+
+func (m *metricsBuilderImpl) WithRegistry(reg *metrics.Registry) MetricsBuilder {
+	m.reg = reg
+	return m
+}
+
+func (m *metricsBuilderImpl) WithRouteFn(fn func(*http.Request) string) MetricsBuilder {
+	m.routeFn = fn
+	return m
+}
+
+func (m *metricsBuilderImpl) Build() func(http.Handler) http.Handler {
+	if !m.cfg.Enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	var (
+		reg     = m.reg
+		routeFn = m.routeFn
+		path    = m.cfg.Path
+		expose  = reg.Handler()
+	)
+	if len(path) == 0 {
+		path = config.DefaultMetricPath
+	}
+	if m.cfg.MaxLabelSets > 0 {
+		reg = reg.WithLabelLimit(m.cfg.MaxLabelSets)
+	}
+	total := reg.Counter(MetricRequestsTotal, "Total number of HTTP requests", "method", "route", "status")
+	inFlight := reg.Gauge(MetricRequestsInFlight, "Number of HTTP requests currently being served", "method")
+	duration := reg.Histogram(MetricRequestDuration, "Duration of HTTP requests in seconds",
+		m.cfg.HistogramBuckets, "method", "route", "status")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == path {
+				expose.ServeHTTP(w, r)
+				return
+			}
+			method := metrics.MethodLabel(r.Method)
+			inFlight.Inc(method)
+			defer inFlight.Dec(method)
+			ir := newRespInterceptor(w, r)
+			next.ServeHTTP(ir, r)
+			ir.done()
+			route := routeFn(r)
+			status := strconv.Itoa(ir.Status())
+			total.Inc(method, route, status)
+			duration.Observe(ir.Duration().Seconds(), method, route, status)
+		})
+	}
+}
+
+// NewMetricsBuilder creates MetricsBuilder that records request counts, in-flight requests
+// and request latency according to provided configuration.
+// Collected metrics are exposed at cfg.Path in Prometheus text exposition format.
+// If telemetry is not enabled, built middleware is no-op.
+func NewMetricsBuilder(cfg *config.TelemetryConfig) MetricsBuilder {
+	return &metricsBuilderImpl{
+		cfg:     cfg,
+		reg:     metrics.DefaultRegistry(),
+		routeFn: PatternRouteFn,
+	}
+}
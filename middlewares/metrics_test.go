@@ -0,0 +1,51 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/rkosegi/go-http-commons/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMethodLabel(t *testing.T) {
+	reg := metrics.NewRegistry()
+	h := NewMetricsBuilder(&config.TelemetryConfig{Enabled: true, MaxLabelSets: 10}).
+		WithRegistry(reg).
+		Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, m := range []string{http.MethodGet, "FOO", "BAR"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(m, "/", nil))
+	}
+	var buf bytes.Buffer
+	_, _ = reg.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `http_requests_in_flight{method="OTHER"} 0`)
+	assert.Contains(t, buf.String(), `http_requests_total{method="OTHER",route="unmatched",status="200"} 2`)
+	assert.NotContains(t, buf.String(), "FOO")
+
+	// label limit of middleware does not leak into registry
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"} {
+		reg.Counter("other_total", "", "key").Inc(k)
+	}
+	buf.Reset()
+	_, _ = reg.WriteTo(&buf)
+	assert.NotContains(t, buf.String(), `other_total{key="__overflow__"}`)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/auth"
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// ErrRateLimited is sent with status 429 when request exceeds rate limit
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitKeyFn extracts key that requests are limited by.
+// Requests with the same key share the same limit, empty key means that request is not limited.
+type RateLimitKeyFn func(r *http.Request) string
+
+// IPKeyFn uses IP address of remote peer as rate limit key.
+// When server is behind reverse proxy, this is address of proxy, unless RemoteAddr is rewritten by another middleware.
+func IPKeyFn(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// HeaderKeyFn uses value of given request header (such as API key) as rate limit key.
+// Requests without that header are limited by IP address.
+//
+// Value of header is not authenticated, so client can bypass limit just by sending different value
+// with every request. Use it only when header is verified upstream (e.g. by API gateway),
+// otherwise prefer PrincipalKeyFn placed after auth middleware.
+func HeaderKeyFn(name string) RateLimitKeyFn {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(name); len(v) > 0 {
+			return "h:" + v
+		}
+		return "ip:" + IPKeyFn(r)
+	}
+}
+
+// PrincipalKeyFn uses subject of authenticated principal as rate limit key.
+// Anonymous requests are limited by IP address. Rate limiting middleware must be placed after auth middleware.
+func PrincipalKeyFn(r *http.Request) string {
+	if p := auth.FromContext(r.Context()); p != nil {
+		return "p:" + p.Subject
+	}
+	return "ip:" + IPKeyFn(r)
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter maintains token bucket for every key
+type limiter struct {
+	limit     int
+	policy    string
+	burst     float64
+	rate      float64
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newLimiter(limit, burst int, period time.Duration) *limiter {
+	if burst == 0 {
+		burst = limit
+	}
+	if period == 0 {
+		period = config.DefaultRateLimitPeriod
+	}
+	return &limiter{
+		limit:   limit,
+		policy:  fmt.Sprintf("%d;w=%s;burst=%d", limit, ceilSeconds(period), burst),
+		burst:   float64(burst),
+		rate:    float64(limit) / period.Seconds(),
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// fillTime returns time needed to refill bucket from given level
+func (l *limiter) fillTime(tokens float64) time.Duration {
+	return time.Duration((l.burst - tokens) / l.rate * float64(time.Second))
+}
+
+// sweep removes buckets that are full, as they are indistinguishable from new ones
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < max(l.fillTime(0), time.Second) {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		if now.Sub(b.last) >= l.fillTime(b.tokens) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// take attempts to take single token from bucket identified by key.
+// It returns remaining number of tokens, time until bucket is full and, if request is rejected,
+// time until next token is available.
+func (l *limiter) take(key string, now time.Time) (allowed bool, remaining int, reset, retry time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	} else {
+		retry = time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	return allowed, int(b.tokens), l.fillTime(b.tokens), retry
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// routeMarker is registered in private http.ServeMux to find out which route limit applies to request
+type routeMarker int
+
+func (routeMarker) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// RateLimitBuilder is interface to support building of rate limiting middleware.
+type RateLimitBuilder interface {
+	// WithKeyFn sets function that extracts key that requests are limited by.
+	// By default, it is determined by configuration, see IPKeyFn, HeaderKeyFn and PrincipalKeyFn.
+	WithKeyFn(fn RateLimitKeyFn) RateLimitBuilder
+
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) RateLimitBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type rateLimitBuilderImpl struct {
+	cfg   *config.RateLimitConfig
+	keyFn RateLimitKeyFn
+	out   output.Interface
+	now   func() time.Time
+}
+
+func (b *rateLimitBuilderImpl) WithKeyFn(fn RateLimitKeyFn) RateLimitBuilder {
+	b.keyFn = fn
+	return b
+}
+
+func (b *rateLimitBuilderImpl) WithOutput(out output.Interface) RateLimitBuilder {
+	b.out = out
+	return b
+}
+
+func (b *rateLimitBuilderImpl) Build() func(http.Handler) http.Handler {
+	if !b.cfg.Enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	var (
+		out      = b.out
+		now      = b.now
+		keyFn    = b.keyFn
+		def      *limiter
+		mux      = http.NewServeMux()
+		limiters = make([]*limiter, len(b.cfg.Routes))
+	)
+	if keyFn == nil {
+		keyFn = IPKeyFn
+		if b.cfg.Key == config.RateLimitConfigKeyHeader {
+			hdr := b.cfg.KeyHeader
+			if len(hdr) == 0 {
+				hdr = config.DefaultRateLimitKeyHeader
+			}
+			keyFn = HeaderKeyFn(hdr)
+		} else if b.cfg.Key == config.RateLimitConfigKeyPrincipal {
+			keyFn = PrincipalKeyFn
+		}
+	}
+	if b.cfg.Limit > 0 {
+		def = newLimiter(b.cfg.Limit, b.cfg.Burst, b.cfg.Period)
+	}
+	for i, route := range b.cfg.Routes {
+		limiters[i] = newLimiter(route.Limit, route.Burst, route.Period)
+		mux.Handle(route.Pattern, routeMarker(i))
+	}
+	limiterFor := func(r *http.Request) *limiter {
+		if len(limiters) > 0 {
+			if h, _ := mux.Handler(r); h != nil {
+				if idx, ok := h.(routeMarker); ok {
+					return limiters[idx]
+				}
+			}
+		}
+		return def
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := limiterFor(r)
+			key := keyFn(r)
+			if l == nil || len(key) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			allowed, remaining, reset, retry := l.take(key, now())
+			h := w.Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(l.limit))
+			h.Set(HeaderRateLimitPolicy, l.policy)
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+			h.Set(HeaderRateLimitReset, ceilSeconds(reset))
+			if !allowed {
+				h.Set("Retry-After", ceilSeconds(retry))
+				out.SendWithStatus(w, output.WrapError(ErrRateLimited, http.StatusTooManyRequests),
+					http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewRateLimitBuilder creates RateLimitBuilder that limits requests according to provided configuration.
+// Configuration is expected to pass config.ServerConfig Check, Build panics on invalid route patterns.
+// Every route has its own token bucket per key, requests that don't match any route
+// are limited by top-level limit, if any.
+// If rate limiting is not enabled, built middleware is no-op.
+func NewRateLimitBuilder(cfg *config.RateLimitConfig) RateLimitBuilder {
+	return &rateLimitBuilderImpl{
+		cfg: cfg,
+		out: output.DefaultOutput(),
+		now: time.Now,
+	}
+}
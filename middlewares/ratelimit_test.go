@@ -0,0 +1,117 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/auth"
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Now()
+	b := NewRateLimitBuilder(&config.RateLimitConfig{
+		Enabled: true,
+		Limit:   2,
+		Period:  time.Second,
+		Routes: []config.RateLimitRoute{
+			{Pattern: "POST /upload", Limit: 1, Period: time.Minute},
+		},
+	}).(*rateLimitBuilderImpl)
+	b.now = func() time.Time { return now }
+	h := b.Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(method, path, ip string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "2;w=1;burst=2", rr.Header().Get(HeaderRateLimitPolicy))
+	assert.Equal(t, "1", rr.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "1", rr.Header().Get(HeaderRateLimitReset))
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/", "10.0.0.1").Code)
+	rr = serve(http.MethodGet, "/", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	// other client has its own bucket
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/", "10.0.0.2").Code)
+
+	// route limit is independent of default one
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/upload", "10.0.0.1").Code)
+	rr = serve(http.MethodPost, "/upload", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "1", rr.Header().Get(HeaderRateLimitLimit))
+
+	// tokens are refilled over time
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(http.MethodPost, "/upload", "10.0.0.1").Code)
+}
+
+func TestLimiterEviction(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(10, 0, time.Second)
+	l.take("a", now)
+	l.take("b", now)
+	assert.Len(t, l.buckets, 2)
+	now = now.Add(2 * time.Second)
+	l.take("b", now)
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestHeaderKeyFn(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	fn := HeaderKeyFn(config.DefaultRateLimitKeyHeader)
+	assert.Equal(t, "ip:10.0.0.1", fn(req))
+	req.Header.Set(config.DefaultRateLimitKeyHeader, "secret")
+	assert.Equal(t, "h:secret", fn(req))
+}
+
+func TestPrincipalKeyFn(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "ip:10.0.0.1", PrincipalKeyFn(req))
+	req = req.WithContext(auth.NewContext(req.Context(), &auth.Principal{Subject: "alice"}))
+	assert.Equal(t, "p:alice", PrincipalKeyFn(req))
+}
+
+func TestRateLimitHeadersWithBurst(t *testing.T) {
+	h := NewRateLimitBuilder(&config.RateLimitConfig{
+		Enabled: true,
+		Limit:   10,
+		Burst:   20,
+		Period:  time.Minute,
+	}).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "10", rr.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "19", rr.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "10;w=60;burst=20", rr.Header().Get(HeaderRateLimitPolicy))
+}
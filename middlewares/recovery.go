@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+// PanicError is sent through output.Interface when handler panics.
+// Its message is intentionally generic, so that internals are not leaked to client,
+// ErrorMappers can still access recovered value and stack trace using errors.As.
+type PanicError struct {
+	// Value is value passed to panic
+	Value interface{}
+	// Stack is stack trace of goroutine at the moment of recovery
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return http.StatusText(http.StatusInternalServerError)
+}
+
+// RecoveryBuilder is interface to support building of panic recovery middleware.
+type RecoveryBuilder interface {
+	// WithLogger sets slog.Logger instance used to log recovered panics. By default, slog.Default() is used.
+	WithLogger(logger *slog.Logger) RecoveryBuilder
+
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) RecoveryBuilder
+
+	// WithoutStackTrace disables logging of stack trace.
+	WithoutStackTrace() RecoveryBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type recoveryBuilderImpl struct {
+	l     *slog.Logger
+	out   output.Interface
+	stack bool
+}
+
+func (r *recoveryBuilderImpl) WithLogger(logger *slog.Logger) RecoveryBuilder {
+	r.l = logger
+	return r
+}
+
+func (r *recoveryBuilderImpl) WithOutput(out output.Interface) RecoveryBuilder {
+	r.out = out
+	return r
+}
+
+func (r *recoveryBuilderImpl) WithoutStackTrace() RecoveryBuilder {
+	r.stack = false
+	return r
+}
+
+func (r *recoveryBuilderImpl) Build() func(http.Handler) http.Handler {
+	var (
+		l     = r.l
+		out   = r.out
+		stack = r.stack
+	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ir := newRespInterceptor(w, req)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// deliberate abort of response, let net/http deal with it
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				pe := &PanicError{Value: rec, Stack: debug.Stack()}
+				attrs := []any{
+					"panic", fmt.Sprint(rec),
+					"method", req.Method,
+					"path", req.URL.Path,
+					"remote", req.RemoteAddr,
+				}
+				if stack {
+					attrs = append(attrs, "stack", string(pe.Stack))
+				}
+				l.ErrorContext(req.Context(), "recovered from panic", attrs...)
+				if ir.WroteHeader() {
+					// part of response is already on the wire, only thing left is to abort connection,
+					// so that client can see that response is incomplete
+					panic(http.ErrAbortHandler)
+				}
+				out.SendWithStatus(ir, output.WrapError(pe, http.StatusInternalServerError),
+					http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(ir, req)
+		})
+	}
+}
+
+// NewRecoveryBuilder creates RecoveryBuilder with the defaults.
+func NewRecoveryBuilder() RecoveryBuilder {
+	return &recoveryBuilderImpl{
+		l:     slog.Default(),
+		out:   output.DefaultOutput(),
+		stack: true,
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecovery(t *testing.T) {
+	var logs bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&logs, nil))
+	out := output.NewBuilder().WithProblemDetails().Build()
+	mw := NewRecoveryBuilder().WithLogger(l).WithOutput(out).Build()
+
+	t.Run("sends problem", func(t *testing.T) {
+		logs.Reset()
+		rr := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/x", nil))
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, output.ProblemContentType, rr.Header().Get("Content-Type"))
+		assert.NotContains(t, rr.Body.String(), "boom")
+		assert.Contains(t, logs.String(), "panic=boom")
+		assert.Contains(t, logs.String(), "path=/x")
+		assert.Contains(t, logs.String(), "recovery_test.go")
+	})
+
+	t.Run("error mapper sees panic", func(t *testing.T) {
+		var recovered interface{}
+		out := output.NewBuilder().WithErrorMapper(func(w http.ResponseWriter, err error) bool {
+			var pe *PanicError
+			if errors.As(err, &pe) {
+				recovered = pe.Value
+				w.WriteHeader(http.StatusTeapot)
+				return true
+			}
+			return false
+		}).Build()
+		rr := httptest.NewRecorder()
+		NewRecoveryBuilder().WithLogger(l).WithOutput(out).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(42)
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusTeapot, rr.Code)
+		assert.Equal(t, 42, recovered)
+	})
+
+	t.Run("headers already sent", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("partial"))
+				panic("boom")
+			})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, strings.HasPrefix(rr.Body.String(), "partial"))
+	})
+
+	t.Run("abort handler", func(t *testing.T) {
+		logs.Reset()
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(http.ErrAbortHandler)
+			})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("flush passes through", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("chunk"))
+			assert.NoError(t, http.NewResponseController(w).Flush())
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, rr.Flushed)
+		assert.Equal(t, "chunk", rr.Body.String())
+	})
+}
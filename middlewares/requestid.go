@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/rkosegi/go-http-commons/requestid"
+)
+
+// RequestIDBuilder is interface to support building of middleware that assigns ID to every request.
+type RequestIDBuilder interface {
+	// WithHeader sets name of header that carries request ID, both in request and response.
+	// By default, requestid.DefaultHeader is used.
+	WithHeader(name string) RequestIDBuilder
+
+	// WithGenerator sets function used to generate ID when request does not carry valid one.
+	// By default, requestid.UUIDv4 is used.
+	WithGenerator(gen requestid.Generator) RequestIDBuilder
+
+	// IgnoreIncoming causes ID sent by client to be ignored, new ID is always generated.
+	IgnoreIncoming() RequestIDBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type requestIDBuilderImpl struct {
+	hdr   string
+	gen   requestid.Generator
+	trust bool
+}
+
+func (b *requestIDBuilderImpl) WithHeader(name string) RequestIDBuilder {
+	b.hdr = name
+	return b
+}
+
+func (b *requestIDBuilderImpl) WithGenerator(gen requestid.Generator) RequestIDBuilder {
+	b.gen = gen
+	return b
+}
+
+func (b *requestIDBuilderImpl) IgnoreIncoming() RequestIDBuilder {
+	b.trust = false
+	return b
+}
+
+func (b *requestIDBuilderImpl) Build() func(http.Handler) http.Handler {
+	var (
+		hdr   = http.CanonicalHeaderKey(b.hdr)
+		gen   = b.gen
+		trust = b.trust
+	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(hdr)
+			if !trust || !requestid.IsValid(id) {
+				id = gen()
+				r.Header.Set(hdr, id)
+			}
+			w.Header().Set(hdr, id)
+			next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+		})
+	}
+}
+
+// NewRequestIDBuilder creates RequestIDBuilder with defaults.
+func NewRequestIDBuilder() RequestIDBuilder {
+	return &requestIDBuilderImpl{
+		hdr:   requestid.DefaultHeader,
+		gen:   requestid.UUIDv4,
+		trust: true,
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var got interface{}
+	h := NewRequestIDBuilder().WithGenerator(func() string {
+		return "generated"
+	}).Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, got = RequestIDReqInfoExtractor()(r)
+	}))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.DefaultHeader, "abc")
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, "abc", got)
+	assert.Equal(t, "abc", rr.Header().Get(requestid.DefaultHeader))
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.DefaultHeader, "bad value")
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, "generated", got)
+	assert.Equal(t, "generated", rr.Header().Get(requestid.DefaultHeader))
+}
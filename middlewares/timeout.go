@@ -0,0 +1,271 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+// DefaultRequestTimeoutHeader is name of request header that carries timeout requested by client.
+// Value is either Go duration (such as "1.5s") or number of seconds.
+const DefaultRequestTimeoutHeader = "X-Request-Timeout"
+
+// ErrRequestTimeout is sent through output.Interface when request processing takes longer than allowed.
+var ErrRequestTimeout = errors.New("request processing timed out")
+
+// TimeoutBuilder is interface to support building of middleware that limits time spent processing request.
+// Handler runs with context that carries deadline, its response is buffered and only sent once handler returns,
+// so that late writes can't interfere with error response sent when timeout expires.
+// Because of buffering, handlers behind this middleware can't stream response.
+type TimeoutBuilder interface {
+	// WithOutput sets output.Interface used to send error response. By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) TimeoutBuilder
+
+	// WithLogger sets slog.Logger used to log panics of handlers that occur after timeout expired,
+	// when there is nobody left to propagate them to. By default, slog.Default() is used.
+	WithLogger(logger *slog.Logger) TimeoutBuilder
+
+	// WithRouteTimeout sets timeout for requests which path starts with given prefix.
+	// If more prefixes match, the longest one wins.
+	WithRouteTimeout(prefix string, d time.Duration) TimeoutBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type routeTimeout struct {
+	prefix string
+	d      time.Duration
+}
+
+type timeoutBuilderImpl struct {
+	cfg    *config.TimeoutConfig
+	out    output.Interface
+	l      *slog.Logger
+	routes []routeTimeout
+}
+
+// handlerPanic is panic of handler goroutine, along with stack captured where it occurred
+type handlerPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (t *timeoutBuilderImpl) WithOutput(out output.Interface) TimeoutBuilder {
+	t.out = out
+	return t
+}
+
+func (t *timeoutBuilderImpl) WithLogger(logger *slog.Logger) TimeoutBuilder {
+	t.l = logger
+	return t
+}
+
+func (t *timeoutBuilderImpl) WithRouteTimeout(prefix string, d time.Duration) TimeoutBuilder {
+	t.routes = append(t.routes, routeTimeout{prefix: prefix, d: d})
+	return t
+}
+
+// parseRequestTimeout parses timeout requested by client
+func parseRequestTimeout(v string) (time.Duration, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return d, true
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+func (t *timeoutBuilderImpl) Build() func(http.Handler) http.Handler {
+	var (
+		out    = t.out
+		l      = t.l
+		def    = t.cfg.Default
+		limit  = t.cfg.Max
+		honor  = t.cfg.HonorClientDeadline
+		hdr    = t.cfg.Header
+		status = t.cfg.StatusCode
+		routes = make([]routeTimeout, len(t.routes))
+	)
+	copy(routes, t.routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].prefix) > len(routes[j].prefix)
+	})
+	if len(hdr) == 0 {
+		hdr = DefaultRequestTimeoutHeader
+	}
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	if limit == 0 {
+		limit = def
+	}
+	timeoutFor := func(r *http.Request) time.Duration {
+		d := def
+		for _, rt := range routes {
+			if strings.HasPrefix(r.URL.Path, rt.prefix) {
+				d = rt.d
+				break
+			}
+		}
+		if honor {
+			if req, ok := parseRequestTimeout(r.Header.Get(hdr)); ok {
+				// without any server-side limit, client can only make things stricter
+				if upper := max(limit, d); upper > 0 {
+					d = min(req, upper)
+				} else {
+					d = req
+				}
+			}
+		}
+		return d
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := timeoutFor(r)
+			if d <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+			tw := &timeoutWriter{h: make(http.Header)}
+			done := make(chan struct{})
+			panicCh := make(chan *handlerPanic, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicCh <- &handlerPanic{value: p, stack: debug.Stack()}
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+			select {
+			case p := <-panicCh:
+				// re-panic in serving goroutine, so that recovery middleware can handle it
+				panic(p.value)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				dst := w.Header()
+				for k, vv := range tw.h {
+					dst[k] = vv
+				}
+				if !tw.wroteHeader {
+					tw.code = http.StatusOK
+				}
+				w.WriteHeader(tw.code)
+				_, _ = w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				// handler keeps running after timeout, its panic can only be logged then
+				go func() {
+					select {
+					case p := <-panicCh:
+						if p.value == http.ErrAbortHandler {
+							return
+						}
+						l.ErrorContext(r.Context(), "handler panicked after request timed out",
+							"panic", fmt.Sprint(p.value),
+							"method", r.Method,
+							"path", r.URL.Path,
+							"stack", string(p.stack))
+					case <-done:
+					}
+				}()
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					// client went away, nobody to respond to
+					tw.err = ctx.Err()
+					return
+				}
+				tw.err = http.ErrHandlerTimeout
+				out.SendWithStatus(w, output.WrapError(ErrRequestTimeout, status), status)
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers response until handler completes
+type timeoutWriter struct {
+	mu          sync.Mutex
+	h           http.Header
+	buf         bytes.Buffer
+	err         error
+	wroteHeader bool
+	code        int
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.err != nil {
+		return 0, tw.err
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.err != nil || tw.wroteHeader {
+		return
+	}
+	tw.writeHeaderLocked(code)
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	tw.wroteHeader = true
+	tw.code = code
+}
+
+// NewTimeoutBuilder creates TimeoutBuilder according to provided configuration.
+// Zero cfg.Default means no timeout, unless one is set for route or requested by client.
+func NewTimeoutBuilder(cfg *config.TimeoutConfig) TimeoutBuilder {
+	return &timeoutBuilderImpl{
+		cfg: cfg,
+		out: output.DefaultOutput(),
+		l:   slog.Default(),
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/rkosegi/go-http-commons/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	var (
+		deadline time.Duration
+		lateErr  = make(chan error, 1)
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dl, _ := r.Context().Deadline()
+		deadline = time.Until(dl)
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			time.Sleep(5 * time.Millisecond)
+			_, err := w.Write([]byte("late"))
+			lateErr <- err
+			return
+		}
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("ok"))
+	})
+	cfg := &config.TimeoutConfig{
+		Default:             time.Second,
+		Max:                 10 * time.Second,
+		HonorClientDeadline: true,
+		StatusCode:          http.StatusGatewayTimeout,
+	}
+	h := NewTimeoutBuilder(cfg).
+		WithOutput(output.NewBuilder().WithProblemDetails().Build()).
+		WithRouteTimeout("/slow", 20*time.Millisecond).
+		WithRouteTimeout("/long", 5*time.Second).
+		Build()(handler)
+
+	t.Run("completes", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get("X-Test"))
+		assert.Equal(t, "ok", rr.Body.String())
+		assert.LessOrEqual(t, deadline, time.Second)
+		assert.Greater(t, deadline, 900*time.Millisecond)
+	})
+	t.Run("route timeout", func(t *testing.T) {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/long/x", nil))
+		assert.Greater(t, deadline, 4*time.Second)
+	})
+	t.Run("client deadline is capped", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(DefaultRequestTimeoutHeader, "1m")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Greater(t, deadline, 9*time.Second)
+		assert.LessOrEqual(t, deadline, 10*time.Second)
+		req.Header.Set(DefaultRequestTimeoutHeader, "0.1")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		assert.LessOrEqual(t, deadline, 100*time.Millisecond)
+	})
+	t.Run("expires", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/slow", nil))
+		assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+		assert.Equal(t, output.ProblemContentType, rr.Header().Get("Content-Type"))
+		assert.ErrorIs(t, <-lateErr, http.ErrHandlerTimeout)
+		assert.NotContains(t, rr.Body.String(), "late")
+	})
+}
+
+func TestParseRequestTimeout(t *testing.T) {
+	d, ok := parseRequestTimeout("1.5s")
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+	d, ok = parseRequestTimeout("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+	_, ok = parseRequestTimeout("-1s")
+	assert.False(t, ok)
+	_, ok = parseRequestTimeout("soon")
+	assert.False(t, ok)
+}
+
+// chanWriter passes every write to channel
+type chanWriter chan string
+
+func (c chanWriter) Write(p []byte) (int, error) {
+	c <- string(p)
+	return len(p), nil
+}
+
+func TestTimeoutLatePanic(t *testing.T) {
+	logs := make(chanWriter, 1)
+	h := NewTimeoutBuilder(&config.TimeoutConfig{Default: 10 * time.Millisecond}).
+		WithLogger(slog.New(slog.NewTextHandler(logs, nil))).
+		Build()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(5 * time.Millisecond)
+		panic("late boom")
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/late", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	select {
+	case line := <-logs:
+		assert.Contains(t, line, "handler panicked after request timed out")
+		assert.Contains(t, line, "panic=\"late boom\"")
+		assert.Contains(t, line, "path=/late")
+		assert.Contains(t, line, "timeout_test.go")
+	case <-time.After(time.Second):
+		assert.Fail(t, "panic was not logged")
+	}
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpenTimeout      = 30 * time.Second
+	DefaultBreakerHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is matched (using errors.Is) by every CircuitOpenError.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is state of single circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed means that requests are passed through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means that requests are rejected without being sent.
+	CircuitOpen
+	// CircuitHalfOpen means that limited number of trial requests is let through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// CircuitOpenError is returned from HttpRequestDoer when request was rejected by open circuit.
+type CircuitOpenError struct {
+	// Key identifies breaker instance, host of request by default.
+	Key string
+	// RetryAt is time when breaker transitions to half-open state.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for %s is open until %s", e.Key, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerFailurePolicy decides whether outcome of request counts as failure.
+// Either resp or err is non-nil, elapsed is duration of the call.
+type BreakerFailurePolicy func(resp *http.Response, err error, elapsed time.Duration) bool
+
+// StateChangeFunc is called whenever breaker identified by key changes its state.
+type StateChangeFunc func(key string, from, to CircuitState)
+
+// CircuitBreakerBuilder is interface to support building of HttpRequestDoer decorator
+// that stops sending requests to failing downstream.
+type CircuitBreakerBuilder interface {
+	// WithFailureThreshold sets number of consecutive failures that opens circuit.
+	// By default, DefaultBreakerFailureThreshold is used.
+	WithFailureThreshold(n int) CircuitBreakerBuilder
+
+	// WithOpenTimeout sets duration for which circuit stays open before trial requests are allowed.
+	// By default, DefaultBreakerOpenTimeout is used.
+	WithOpenTimeout(d time.Duration) CircuitBreakerBuilder
+
+	// WithHalfOpenRequests sets number of trial requests in half-open state.
+	// All of them must succeed to close the circuit.
+	// By default, DefaultBreakerHalfOpenRequests is used.
+	WithHalfOpenRequests(n int) CircuitBreakerBuilder
+
+	// WithFailureStatusClasses sets status classes (e.g. 5 for 5xx) that are counted as failures.
+	// By default, only 5xx responses are failures.
+	WithFailureStatusClasses(classes ...int) CircuitBreakerBuilder
+
+	// WithSlowCallThreshold sets duration above which call is counted as failure, regardless of its outcome.
+	// Zero (default) disables latency check.
+	WithSlowCallThreshold(d time.Duration) CircuitBreakerBuilder
+
+	// WithFailurePolicy sets custom BreakerFailurePolicy, which replaces checks based on errors,
+	// status classes and latency.
+	WithFailurePolicy(policy BreakerFailurePolicy) CircuitBreakerBuilder
+
+	// WithKeyFunc sets function that maps request to breaker instance.
+	// By default, separate breaker is maintained for every host.
+	WithKeyFunc(fn func(req *http.Request) string) CircuitBreakerBuilder
+
+	// OnStateChange sets callback that is invoked on every state transition.
+	OnStateChange(fn StateChangeFunc) CircuitBreakerBuilder
+
+	// WithLogger sets slog.Logger used to log state transitions. By default, slog.Default() is used.
+	WithLogger(l *slog.Logger) CircuitBreakerBuilder
+
+	// Build creates decorator of provided HttpRequestDoer.
+	Build(d HttpRequestDoer) HttpRequestDoer
+}
+
+type breakerBuilderImpl struct {
+	threshold   int
+	openTimeout time.Duration
+	halfOpen    int
+	classes     map[int]bool
+	slow        time.Duration
+	policy      BreakerFailurePolicy
+	keyFn       func(req *http.Request) string
+	onChange    StateChangeFunc
+	l           *slog.Logger
+	now         func() time.Time
+}
+
+func (b *breakerBuilderImpl) WithFailureThreshold(n int) CircuitBreakerBuilder {
+	b.threshold = n
+	return b
+}
+
+func (b *breakerBuilderImpl) WithOpenTimeout(d time.Duration) CircuitBreakerBuilder {
+	b.openTimeout = d
+	return b
+}
+
+func (b *breakerBuilderImpl) WithHalfOpenRequests(n int) CircuitBreakerBuilder {
+	b.halfOpen = n
+	return b
+}
+
+func (b *breakerBuilderImpl) WithFailureStatusClasses(classes ...int) CircuitBreakerBuilder {
+	b.classes = make(map[int]bool, len(classes))
+	for _, c := range classes {
+		b.classes[c] = true
+	}
+	return b
+}
+
+func (b *breakerBuilderImpl) WithSlowCallThreshold(d time.Duration) CircuitBreakerBuilder {
+	b.slow = d
+	return b
+}
+
+func (b *breakerBuilderImpl) WithFailurePolicy(policy BreakerFailurePolicy) CircuitBreakerBuilder {
+	b.policy = policy
+	return b
+}
+
+func (b *breakerBuilderImpl) WithKeyFunc(fn func(req *http.Request) string) CircuitBreakerBuilder {
+	b.keyFn = fn
+	return b
+}
+
+func (b *breakerBuilderImpl) OnStateChange(fn StateChangeFunc) CircuitBreakerBuilder {
+	b.onChange = fn
+	return b
+}
+
+func (b *breakerBuilderImpl) WithLogger(l *slog.Logger) CircuitBreakerBuilder {
+	b.l = l
+	return b
+}
+
+func (b *breakerBuilderImpl) Build(d HttpRequestDoer) HttpRequestDoer {
+	c := *b
+	c.threshold = max(c.threshold, 1)
+	c.halfOpen = max(c.halfOpen, 1)
+	if c.policy == nil {
+		c.policy = func(resp *http.Response, err error, elapsed time.Duration) bool {
+			if c.slow > 0 && elapsed > c.slow {
+				return true
+			}
+			if err != nil {
+				return true
+			}
+			return c.classes[resp.StatusCode/100]
+		}
+	}
+	return &breakingHttpDoer{d: d, cfg: &c, breakers: map[string]*breaker{}}
+}
+
+type breaker struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	inflight  int
+	successes int
+	// gen is incremented on every state transition, so that outcome of request
+	// admitted in earlier state is not accounted into current one
+	gen uint64
+}
+
+type stateChange struct {
+	key      string
+	from, to CircuitState
+}
+
+type breakingHttpDoer struct {
+	d        HttpRequestDoer
+	cfg      *breakerBuilderImpl
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// transition must be called with lock held
+func (r *breakingHttpDoer) transition(key string, b *breaker, to CircuitState) *stateChange {
+	from := b.state
+	b.state = to
+	b.gen++
+	b.failures, b.inflight, b.successes = 0, 0, 0
+	if to == CircuitOpen {
+		b.openedAt = r.cfg.now()
+	}
+	return &stateChange{key: key, from: from, to: to}
+}
+
+// notify reports state change, must be called without lock held
+func (r *breakingHttpDoer) notify(ctx context.Context, sc *stateChange) {
+	if sc == nil {
+		return
+	}
+	r.cfg.l.InfoContext(ctx, "circuit breaker state changed", "key", sc.key, "from", sc.from, "to", sc.to)
+	if r.cfg.onChange != nil {
+		r.cfg.onChange(sc.key, sc.from, sc.to)
+	}
+}
+
+// acquire checks whether request can proceed, returning non-nil error if it can't.
+// Generation of breaker at the time of admission is returned.
+func (r *breakingHttpDoer) acquire(key string) (uint64, *stateChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.breakers[key]
+	if !ok {
+		b = &breaker{}
+		r.breakers[key] = b
+	}
+	var sc *stateChange
+	if b.state == CircuitOpen {
+		retryAt := b.openedAt.Add(r.cfg.openTimeout)
+		if r.cfg.now().Before(retryAt) {
+			return 0, nil, &CircuitOpenError{Key: key, RetryAt: retryAt}
+		}
+		sc = r.transition(key, b, CircuitHalfOpen)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.inflight+b.successes >= r.cfg.halfOpen {
+			return 0, sc, &CircuitOpenError{Key: key, RetryAt: r.cfg.now()}
+		}
+		b.inflight++
+	}
+	return b.gen, sc, nil
+}
+
+func (r *breakingHttpDoer) release(key string, gen uint64, failed bool) *stateChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.breakers[key]
+	if b.gen != gen {
+		// outcome of request that was admitted in different state
+		return nil
+	}
+	switch b.state {
+	case CircuitClosed:
+		if !failed {
+			b.failures = 0
+			return nil
+		}
+		b.failures++
+		if b.failures >= r.cfg.threshold {
+			return r.transition(key, b, CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			return r.transition(key, b, CircuitOpen)
+		}
+		b.inflight--
+		b.successes++
+		if b.successes >= r.cfg.halfOpen {
+			return r.transition(key, b, CircuitClosed)
+		}
+	}
+	return nil
+}
+
+// abandon releases trial slot without recording outcome
+func (r *breakingHttpDoer) abandon(key string, gen uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b := r.breakers[key]; b.gen == gen && b.state == CircuitHalfOpen {
+		b.inflight--
+	}
+}
+
+func (r *breakingHttpDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	key := r.cfg.keyFn(req)
+	gen, sc, err := r.acquire(key)
+	r.notify(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+	start := r.cfg.now()
+	resp, err := r.d.Do(req)
+	// caller giving up says nothing about health of downstream
+	if err != nil && ctx.Err() != nil {
+		r.abandon(key, gen)
+		return resp, err
+	}
+	r.notify(ctx, r.release(key, gen, r.cfg.policy(resp, err, r.cfg.now().Sub(start))))
+	return resp, err
+}
+
+// NewCircuitBreakerBuilder creates CircuitBreakerBuilder with defaults.
+func NewCircuitBreakerBuilder() CircuitBreakerBuilder {
+	return (&breakerBuilderImpl{
+		threshold:   DefaultBreakerFailureThreshold,
+		openTimeout: DefaultBreakerOpenTimeout,
+		halfOpen:    DefaultBreakerHalfOpenRequests,
+		keyFn: func(req *http.Request) string {
+			return req.URL.Host
+		},
+		l:   slog.Default(),
+		now: time.Now,
+	}).WithFailureStatusClasses(5)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		calls       int
+		status      = http.StatusInternalServerError
+		now         = time.Now()
+		transitions []string
+	)
+	b := NewCircuitBreakerBuilder().
+		WithFailureThreshold(2).
+		WithOpenTimeout(time.Minute).
+		OnStateChange(func(key string, from, to CircuitState) {
+			transitions = append(transitions, key+":"+from.String()+"->"+to.String())
+		}).(*breakerBuilderImpl)
+	b.now = func() time.Time { return now }
+	d := b.Build(DoerFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+	}))
+	call := func(host string) error {
+		req, _ := http.NewRequest(http.MethodGet, "http://"+host+"/", nil)
+		_, err := d.Do(req)
+		return err
+	}
+
+	assert.NoError(t, call("a"))
+	assert.NoError(t, call("a"))
+	err := call("a")
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	var coe *CircuitOpenError
+	assert.True(t, errors.As(err, &coe))
+	assert.Equal(t, "a", coe.Key)
+	assert.Equal(t, now.Add(time.Minute), coe.RetryAt)
+	assert.Equal(t, 2, calls)
+
+	// other host has its own breaker
+	assert.NoError(t, call("b"))
+	assert.Equal(t, 3, calls)
+
+	// trial request fails, circuit opens again
+	now = now.Add(time.Minute)
+	assert.NoError(t, call("a"))
+	assert.ErrorIs(t, call("a"), ErrCircuitOpen)
+
+	// trial request succeeds, circuit closes
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	assert.NoError(t, call("a"))
+	assert.NoError(t, call("a"))
+	assert.Equal(t, []string{
+		"a:closed->open",
+		"a:open->half-open",
+		"a:half-open->open",
+		"a:open->half-open",
+		"a:half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreakerSlowCall(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreakerBuilder().
+		WithFailureThreshold(1).
+		WithSlowCallThreshold(time.Second).(*breakerBuilderImpl)
+	b.now = func() time.Time { return now }
+	d := b.Build(DoerFunc(func(req *http.Request) (*http.Response, error) {
+		now = now.Add(2 * time.Second)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	_, err := d.Do(req)
+	assert.NoError(t, err)
+	_, err = d.Do(req)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestCircuitBreakerStaleOutcome(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreakerBuilder().
+		WithFailureThreshold(1).
+		WithOpenTimeout(time.Minute).(*breakerBuilderImpl)
+	b.now = func() time.Time { return now }
+	d := b.Build(nil).(*breakingHttpDoer)
+
+	// slow request admitted while closed
+	stale, _, err := d.acquire("a")
+	assert.NoError(t, err)
+	// another request fails and opens circuit
+	gen, _, err := d.acquire("a")
+	assert.NoError(t, err)
+	assert.Equal(t, CircuitOpen, d.release("a", gen, true).to)
+
+	// trial request admitted in half-open state
+	now = now.Add(time.Minute)
+	trial, sc, err := d.acquire("a")
+	assert.NoError(t, err)
+	assert.Equal(t, CircuitHalfOpen, sc.to)
+
+	// success of stale request must not close circuit
+	assert.Nil(t, d.release("a", stale, false))
+	assert.Equal(t, CircuitHalfOpen, d.breakers["a"].state)
+	assert.Equal(t, CircuitClosed, d.release("a", trial, false).to)
+}
@@ -17,30 +17,234 @@ limitations under the License.
 package openapi
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"path"
+	"strings"
+	"sync"
 
+	"github.com/rkosegi/go-http-commons/config"
 	"github.com/rkosegi/go-http-commons/output"
+	"gopkg.in/yaml.v3"
 )
 
-var out = output.DefaultOutput()
+const (
+	jsonContentType = "application/json"
+	yamlContentType = "application/yaml"
+
+	// maxSpecCacheEntries limits number of cached representations of spec,
+	// as with server rewrite every host that spec is requested through has its own.
+	maxSpecCacheEntries = 64
+)
+
+var (
+	out = output.DefaultOutput()
+
+	ErrSpecNotFound = errors.New("spec not found")
+)
 
 // SpecFileProvider is function similar to PathToRawSpec generated by oapi-codegen
 // see https://github.com/oapi-codegen/oapi-codegen/blob/bdc4edce7476be0791dc670d695fd78378e0e9b6/pkg/codegen/templates/inline.tmpl#L38
 type SpecFileProvider func(pathToFile string) map[string]func() ([]byte, error)
 
+// SpecHandlerBuilder is interface to support building of handler that serves openapi spec.
+type SpecHandlerBuilder interface {
+	// WithServerRewrite causes servers block of spec to be replaced with single server
+	// that matches host of incoming request and APIPrefix from provided configuration.
+	WithServerRewrite(cfg *config.ServerConfig) SpecHandlerBuilder
+
+	// WithForwardedHeaders causes X-Forwarded-Proto and X-Forwarded-Host headers to be used
+	// by server rewrite. Enable it only when server is behind reverse proxy that sets (or strips) them,
+	// otherwise any client can make spec point to arbitrary server.
+	WithForwardedHeaders() SpecHandlerBuilder
+
+	// Build creates http.HandlerFunc using current state of this builder.
+	Build() http.HandlerFunc
+}
+
+type specHandlerBuilderImpl struct {
+	prov      SpecFileProvider
+	serverCfg *config.ServerConfig
+	forwarded bool
+}
+
+func (s *specHandlerBuilderImpl) WithServerRewrite(cfg *config.ServerConfig) SpecHandlerBuilder {
+	s.serverCfg = cfg
+	return s
+}
+
+func (s *specHandlerBuilderImpl) WithForwardedHeaders() SpecHandlerBuilder {
+	s.forwarded = true
+	return s
+}
+
+// contentTypeFor determines representation of spec based on extension of requested path or Accept header.
+func contentTypeFor(r *http.Request) string {
+	switch strings.ToLower(path.Ext(r.URL.Path)) {
+	case ".yaml", ".yml":
+		return yamlContentType
+	case ".json":
+		return jsonContentType
+	}
+	return output.NegotiateContentType(r.Header.Get("Accept"), []string{jsonContentType, yamlContentType})
+}
+
+// serverURL computes base URL of API as seen by client
+func serverURL(r *http.Request, apiPrefix string, forwarded bool) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if forwarded {
+		if fp := r.Header.Get("X-Forwarded-Proto"); len(fp) > 0 {
+			scheme = fp
+		}
+		if fh := r.Header.Get("X-Forwarded-Host"); len(fh) > 0 {
+			host = fh
+		}
+	}
+	return scheme + "://" + host + "/" + strings.TrimPrefix(apiPrefix, "/")
+}
+
+func rewriteServers(data []byte, url string) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc["servers"] = []interface{}{map[string]interface{}{"url": url}}
+	return json.Marshal(doc)
+}
+
+// jsonToYAML converts JSON document to YAML, preserving order of keys
+func jsonToYAML(data []byte) ([]byte, error) {
+	var n yaml.Node
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+	resetStyle(&n)
+	return yaml.Marshal(&n)
+}
+
+// resetStyle switches nodes decoded from JSON to block style
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+// etagMatches checks if any of entity tags in If-None-Match header matches etag, using weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// specRepr is single representation of spec, along with its entity tag
+type specRepr struct {
+	data []byte
+	etag string
+}
+
+// specCache holds representations of spec, so that they are not computed on every request
+type specCache struct {
+	mu      sync.Mutex
+	entries map[string]*specRepr
+}
+
+func (c *specCache) get(key string, compute func() ([]byte, error)) (*specRepr, error) {
+	c.mu.Lock()
+	sr, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		return sr, nil
+	}
+	data, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	sr = &specRepr{data: data, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) < maxSpecCacheEntries {
+		c.entries[key] = sr
+	}
+	return sr, nil
+}
+
+func (s *specHandlerBuilderImpl) Build() http.HandlerFunc {
+	var (
+		prov      = s.prov
+		serverCfg = s.serverCfg
+		forwarded = s.forwarded
+		cache     = &specCache{entries: map[string]*specRepr{}}
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		fn, ok := prov(r.URL.Path)[r.URL.Path]
+		if !ok || fn == nil {
+			out.SendWithStatus(w, ErrSpecNotFound, http.StatusNotFound)
+			return
+		}
+		ct := contentTypeFor(r)
+		if len(ct) == 0 {
+			out.SendWithStatus(w, output.ErrNotAcceptable, http.StatusNotAcceptable)
+			return
+		}
+		var url string
+		if serverCfg != nil {
+			url = serverURL(r, serverCfg.APIPrefix, forwarded)
+		}
+		sr, err := cache.get(r.URL.Path+"\x00"+ct+"\x00"+url, func() ([]byte, error) {
+			data, err := fn()
+			if err == nil && len(url) > 0 {
+				data, err = rewriteServers(data, url)
+			}
+			if err == nil && ct == yamlContentType {
+				data, err = jsonToYAML(data)
+			}
+			return data, err
+		})
+		if err != nil {
+			out.SendWithStatus(w, err, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("ETag", sr.etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Add("Vary", "Accept")
+		if len(url) > 0 && forwarded {
+			w.Header().Add("Vary", "X-Forwarded-Proto, X-Forwarded-Host")
+		}
+		if inm := r.Header.Get("If-None-Match"); len(inm) > 0 && etagMatches(inm, sr.etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", ct)
+		_, _ = w.Write(sr.data)
+	}
+}
+
+// NewSpecHandlerBuilder creates SpecHandlerBuilder for given SpecFileProvider.
+func NewSpecHandlerBuilder(prov SpecFileProvider) SpecHandlerBuilder {
+	return &specHandlerBuilderImpl{prov: prov}
+}
+
 // SpecHandler is http handler that serves openapi spec embedded into application.
+// Spec is served as YAML if requested path ends with .yaml or .yml, as JSON if it ends with .json,
+// otherwise representation is selected using Accept header.
+// Response carries strong ETag, so conditional requests are answered with 304 Not Modified.
 // common use of this handler is something like:
 //
 //	r := mux.NewRouter()
 //	r.HandleFunc("/spec/opeanapi.v1.json", openapi.SpecHandler(api.PathToRawSpec))
 func SpecHandler(prov SpecFileProvider) func(w http.ResponseWriter, r *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if data, err := prov(r.URL.Path)[r.URL.Path](); err != nil {
-			out.SendWithStatus(w, err, http.StatusInternalServerError)
-			return
-		} else {
-			w.Header().Set("Content-Type", "application/json")
-			_, _ = w.Write(data)
-		}
-	}
+	return NewSpecHandlerBuilder(prov).Build()
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProvider(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if pathToFile == "/spec.json" || pathToFile == "/spec.yaml" || pathToFile == "/spec" {
+		res[pathToFile] = func() ([]byte, error) {
+			return []byte(`{"openapi":"3.0.0","info":{"title":"test","version":"1.0.0"},"paths":{}}`), nil
+		}
+	}
+	return res
+}
+
+func TestSpecHandler(t *testing.T) {
+	h := SpecHandler(testProvider)
+	serve := func(path string, hdrs map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range hdrs {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec
+	}
+
+	t.Run("unknown path", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve("/other.json", nil).Code)
+	})
+	t.Run("json", func(t *testing.T) {
+		rec := serve("/spec.json", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+	t.Run("yaml by extension", func(t *testing.T) {
+		rec := serve("/spec.yaml", nil)
+		assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "openapi: 3.0.0\ninfo:\n    title: test\n    version: 1.0.0\npaths: {}\n", rec.Body.String())
+	})
+	t.Run("yaml by accept", func(t *testing.T) {
+		rec := serve("/spec", map[string]string{"Accept": "application/yaml"})
+		assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	})
+	t.Run("not modified", func(t *testing.T) {
+		etag := serve("/spec.json", nil).Header().Get("ETag")
+		rec := serve("/spec.json", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+	t.Run("server rewrite", func(t *testing.T) {
+		h := NewSpecHandlerBuilder(testProvider).WithServerRewrite(&config.ServerConfig{APIPrefix: "/api/v1"}).Build()
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/spec.json", nil)
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		assert.Contains(t, rec.Body.String(), `"servers":[{"url":"http://example.com/api/v1"}]`)
+	})
+	t.Run("forwarded headers", func(t *testing.T) {
+		cfg := &config.ServerConfig{APIPrefix: "/api/v1"}
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/spec.json", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "evil.com")
+
+		rec := httptest.NewRecorder()
+		NewSpecHandlerBuilder(testProvider).WithServerRewrite(cfg).Build()(rec, req)
+		assert.Contains(t, rec.Body.String(), `"servers":[{"url":"http://example.com/api/v1"}]`)
+
+		rec = httptest.NewRecorder()
+		NewSpecHandlerBuilder(testProvider).WithServerRewrite(cfg).WithForwardedHeaders().Build()(rec, req)
+		assert.Contains(t, rec.Body.String(), `"servers":[{"url":"https://evil.com/api/v1"}]`)
+		assert.Contains(t, rec.Header().Values("Vary"), "X-Forwarded-Proto, X-Forwarded-Host")
+	})
+	t.Run("representation is cached", func(t *testing.T) {
+		calls := 0
+		h := SpecHandler(func(pathToFile string) map[string]func() ([]byte, error) {
+			return map[string]func() ([]byte, error){pathToFile: func() ([]byte, error) {
+				calls++
+				return []byte(`{}`), nil
+			}}
+		})
+		for range 3 {
+			h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/spec.yaml", nil))
+		}
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rkosegi/go-http-commons/metrics"
+	"github.com/rkosegi/go-http-commons/tracing"
+)
+
+const (
+	MetricClientRequestsTotal    = "http_client_requests_total"
+	MetricClientRequestsInFlight = "http_client_requests_in_flight"
+	MetricClientRequestDuration  = "http_client_request_duration_seconds"
+
+	// ClientErrorStatus is status label value used when no response was received
+	ClientErrorStatus = "error"
+)
+
+// ClientInstrumentationBuilder is interface to support building of HttpRequestDoer decorator
+// that records metrics, logs outgoing calls and propagates trace context.
+type ClientInstrumentationBuilder interface {
+	// WithRegistry sets metrics.Registry where metrics are recorded.
+	// By default, metrics.DefaultRegistry is used.
+	WithRegistry(reg *metrics.Registry) ClientInstrumentationBuilder
+
+	// WithBuckets sets upper bounds of latency histogram buckets.
+	// By default, metrics.DefaultBuckets are used.
+	WithBuckets(buckets []float64) ClientInstrumentationBuilder
+
+	// WithLogger sets slog.Logger used to log calls. By default, slog.Default() is used.
+	WithLogger(l *slog.Logger) ClientInstrumentationBuilder
+
+	// WithLevel sets level of log record emitted for every call. By default, slog.LevelDebug is used.
+	WithLevel(level slog.Level) ClientInstrumentationBuilder
+
+	// WithoutTracing disables propagation of traceparent and tracestate headers.
+	WithoutTracing() ClientInstrumentationBuilder
+
+	// Build creates decorator of provided HttpRequestDoer.
+	Build(d HttpRequestDoer) HttpRequestDoer
+}
+
+type clientInstrumentationBuilderImpl struct {
+	reg     *metrics.Registry
+	buckets []float64
+	l       *slog.Logger
+	level   slog.Level
+	tracing bool
+}
+
+func (c *clientInstrumentationBuilderImpl) WithRegistry(reg *metrics.Registry) ClientInstrumentationBuilder {
+	c.reg = reg
+	return c
+}
+
+func (c *clientInstrumentationBuilderImpl) WithBuckets(buckets []float64) ClientInstrumentationBuilder {
+	c.buckets = buckets
+	return c
+}
+
+func (c *clientInstrumentationBuilderImpl) WithLogger(l *slog.Logger) ClientInstrumentationBuilder {
+	c.l = l
+	return c
+}
+
+func (c *clientInstrumentationBuilderImpl) WithLevel(level slog.Level) ClientInstrumentationBuilder {
+	c.level = level
+	return c
+}
+
+func (c *clientInstrumentationBuilderImpl) WithoutTracing() ClientInstrumentationBuilder {
+	c.tracing = false
+	return c
+}
+
+func (c *clientInstrumentationBuilderImpl) Build(d HttpRequestDoer) HttpRequestDoer {
+	return &instrumentedHttpDoer{
+		d:       d,
+		l:       c.l,
+		level:   c.level,
+		tracing: c.tracing,
+		total: c.reg.Counter(MetricClientRequestsTotal, "Total number of outgoing HTTP requests",
+			"host", "method", "status"),
+		inFlight: c.reg.Gauge(MetricClientRequestsInFlight, "Number of outgoing HTTP requests in progress",
+			"host"),
+		duration: c.reg.Histogram(MetricClientRequestDuration, "Duration of outgoing HTTP requests in seconds",
+			c.buckets, "host", "method", "status"),
+	}
+}
+
+type instrumentedHttpDoer struct {
+	d        HttpRequestDoer
+	l        *slog.Logger
+	level    slog.Level
+	tracing  bool
+	total    *metrics.CounterVec
+	inFlight *metrics.GaugeVec
+	duration *metrics.HistogramVec
+}
+
+func (i *instrumentedHttpDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attrs := make([]any, 0, 12)
+	if sc, ok := tracing.FromContext(ctx); ok && i.tracing {
+		sc = sc.Child()
+		// don't mutate headers of caller's request
+		req = req.Clone(ctx)
+		tracing.Inject(req.Header, sc)
+		attrs = append(attrs, "trace_id", sc.TraceIDString(), "span_id", sc.SpanIDString())
+	}
+	host := req.URL.Host
+	i.inFlight.Inc(host)
+	start := time.Now()
+	resp, err := i.d.Do(req)
+	elapsed := time.Since(start)
+	i.inFlight.Dec(host)
+
+	status := ClientErrorStatus
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	method := metrics.MethodLabel(req.Method)
+	i.total.Inc(host, method, status)
+	i.duration.Observe(elapsed.Seconds(), host, method, status)
+
+	attrs = append(attrs, "method", req.Method, "url", req.URL.String(), "status", status, "duration", elapsed)
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	i.l.Log(ctx, i.level, "http client call", attrs...)
+	return resp, err
+}
+
+// NewClientInstrumentationBuilder creates ClientInstrumentationBuilder with defaults.
+func NewClientInstrumentationBuilder() ClientInstrumentationBuilder {
+	return &clientInstrumentationBuilderImpl{
+		reg:     metrics.DefaultRegistry(),
+		l:       slog.Default(),
+		level:   slog.LevelDebug,
+		tracing: true,
+	}
+}
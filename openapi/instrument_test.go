@@ -0,0 +1,72 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/metrics"
+	"github.com/rkosegi/go-http-commons/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientInstrumentation(t *testing.T) {
+	var got http.Header
+	reg := metrics.NewRegistry()
+	d := NewClientInstrumentationBuilder().WithRegistry(reg).Build(DoerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header
+		if req.Method == http.MethodPost {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	sc, _ := tracing.Parse("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "vendor=x")
+	req, _ := http.NewRequestWithContext(tracing.NewContext(t.Context(), sc), http.MethodGet, "http://api.local/x", nil)
+	_, err := d.Do(req)
+	assert.NoError(t, err)
+	out, err := tracing.Extract(got)
+	assert.NoError(t, err)
+	assert.Equal(t, sc.TraceID, out.TraceID)
+	assert.NotEqual(t, sc.SpanID, out.SpanID)
+	assert.Equal(t, "vendor=x", out.State)
+	assert.Empty(t, req.Header.Get(tracing.TraceParentHeader))
+
+	req, _ = http.NewRequest(http.MethodPost, "http://api.local/x", nil)
+	_, err = d.Do(req)
+	assert.Error(t, err)
+	assert.Empty(t, got.Get(tracing.TraceParentHeader))
+
+	// empty method means GET, extension methods share single series
+	req, _ = http.NewRequest("", "http://api.local/x", nil)
+	_, _ = d.Do(req)
+	req, _ = http.NewRequest("PURGE", "http://api.local/x", nil)
+	_, _ = d.Do(req)
+
+	var buf bytes.Buffer
+	_, _ = reg.WriteTo(&buf)
+	text := buf.String()
+	assert.True(t, strings.Contains(text, `http_client_requests_total{host="api.local",method="GET",status="200"} 2`))
+	assert.True(t, strings.Contains(text, `http_client_requests_total{host="api.local",method="OTHER",status="200"} 1`))
+	assert.True(t, strings.Contains(text, `http_client_requests_total{host="api.local",method="POST",status="error"} 1`))
+	assert.True(t, strings.Contains(text, `http_client_request_duration_seconds_count{host="api.local",method="GET",status="200"} 2`))
+	assert.True(t, strings.Contains(text, `http_client_requests_in_flight{host="api.local"} 0`))
+}
@@ -26,6 +26,13 @@ type HttpRequestDoer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// DoerFunc is adapter to allow use of ordinary function as HttpRequestDoer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 type responseLoggingHttpDoer struct {
 	d HttpRequestDoer
 	l *slog.Logger
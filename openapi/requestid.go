@@ -0,0 +1,47 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/rkosegi/go-http-commons/requestid"
+)
+
+type requestIDForwardingHttpDoer struct {
+	d   HttpRequestDoer
+	hdr string
+}
+
+func (r *requestIDForwardingHttpDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if id := requestid.FromContext(ctx); len(id) > 0 && len(req.Header.Get(r.hdr)) == 0 {
+		req = req.Clone(ctx)
+		req.Header.Set(r.hdr, id)
+	}
+	return r.d.Do(req)
+}
+
+// ClientRequestIDForwarder decorates HttpRequestDoer so that request ID found in context of outgoing request
+// is sent downstream in given header. If header is empty, requestid.DefaultHeader is used.
+// Header that is already set on request is left untouched.
+func ClientRequestIDForwarder(d HttpRequestDoer, header string) HttpRequestDoer {
+	if len(header) == 0 {
+		header = requestid.DefaultHeader
+	}
+	return &requestIDForwardingHttpDoer{d: d, hdr: header}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rkosegi/go-http-commons/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRequestIDForwarder(t *testing.T) {
+	var got string
+	d := ClientRequestIDForwarder(DoerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get(requestid.DefaultHeader)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), "")
+	req, _ := http.NewRequestWithContext(requestid.NewContext(t.Context(), "abc"), http.MethodGet, "http://api.local/", nil)
+	_, err := d.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", got)
+	assert.Empty(t, req.Header.Get(requestid.DefaultHeader))
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// DefaultRetryableStatuses are status codes that are retried by default
+	DefaultRetryableStatuses = []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+
+	// DefaultRetryableMethods are idempotent methods that are retried by default
+	DefaultRetryableMethods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodTrace,
+		http.MethodPut,
+		http.MethodDelete,
+	}
+)
+
+const (
+	DefaultRetryAttempts     = 3
+	DefaultRetryInitialDelay = 100 * time.Millisecond
+	DefaultRetryMaxDelay     = 5 * time.Second
+)
+
+// RetryPolicy decides whether request should be retried, given outcome of the last attempt.
+// Either resp or err is non-nil.
+type RetryPolicy func(req *http.Request, resp *http.Response, err error) bool
+
+// RetryBuilder is interface to support building of HttpRequestDoer decorator that retries failed requests.
+// Delay between attempts grows exponentially, with full jitter applied.
+type RetryBuilder interface {
+	// WithMaxAttempts sets maximum number of attempts, including the first one.
+	// By default, DefaultRetryAttempts is used.
+	WithMaxAttempts(n int) RetryBuilder
+
+	// WithBackoff sets initial delay and upper bound of delay between attempts.
+	// By default, DefaultRetryInitialDelay and DefaultRetryMaxDelay are used.
+	WithBackoff(initial, max time.Duration) RetryBuilder
+
+	// WithoutJitter disables randomization of delays.
+	WithoutJitter() RetryBuilder
+
+	// WithRetryableStatuses sets status codes that are considered retryable.
+	// By default, DefaultRetryableStatuses are used.
+	WithRetryableStatuses(codes ...int) RetryBuilder
+
+	// WithRetryableMethods sets methods that can be retried.
+	// By default, DefaultRetryableMethods are used.
+	WithRetryableMethods(methods ...string) RetryBuilder
+
+	// WithPolicy sets custom RetryPolicy, which replaces decision based on status codes and errors.
+	// Methods are still checked using list of retryable methods.
+	WithPolicy(policy RetryPolicy) RetryBuilder
+
+	// WithMaxElapsedTime sets upper bound of total time spent by retrying.
+	// Deadline of request context is always honored.
+	WithMaxElapsedTime(d time.Duration) RetryBuilder
+
+	// WithLogger sets slog.Logger used to log retries. By default, slog.Default() is used.
+	WithLogger(l *slog.Logger) RetryBuilder
+
+	// Build creates decorator of provided HttpRequestDoer.
+	Build(d HttpRequestDoer) HttpRequestDoer
+}
+
+type retryBuilderImpl struct {
+	attempts   int
+	initial    time.Duration
+	max        time.Duration
+	jitter     bool
+	statuses   map[int]bool
+	methods    map[string]bool
+	policy     RetryPolicy
+	maxElapsed time.Duration
+	l          *slog.Logger
+}
+
+func (r *retryBuilderImpl) WithMaxAttempts(n int) RetryBuilder {
+	r.attempts = n
+	return r
+}
+
+func (r *retryBuilderImpl) WithBackoff(initial, max time.Duration) RetryBuilder {
+	r.initial = initial
+	r.max = max
+	return r
+}
+
+func (r *retryBuilderImpl) WithoutJitter() RetryBuilder {
+	r.jitter = false
+	return r
+}
+
+func (r *retryBuilderImpl) WithRetryableStatuses(codes ...int) RetryBuilder {
+	r.statuses = make(map[int]bool, len(codes))
+	for _, c := range codes {
+		r.statuses[c] = true
+	}
+	return r
+}
+
+func (r *retryBuilderImpl) WithRetryableMethods(methods ...string) RetryBuilder {
+	r.methods = make(map[string]bool, len(methods))
+	for _, m := range methods {
+		r.methods[m] = true
+	}
+	return r
+}
+
+func (r *retryBuilderImpl) WithPolicy(policy RetryPolicy) RetryBuilder {
+	r.policy = policy
+	return r
+}
+
+func (r *retryBuilderImpl) WithMaxElapsedTime(d time.Duration) RetryBuilder {
+	r.maxElapsed = d
+	return r
+}
+
+func (r *retryBuilderImpl) WithLogger(l *slog.Logger) RetryBuilder {
+	r.l = l
+	return r
+}
+
+func (r *retryBuilderImpl) Build(d HttpRequestDoer) HttpRequestDoer {
+	c := *r
+	if c.policy == nil {
+		c.policy = func(_ *http.Request, resp *http.Response, err error) bool {
+			if err != nil {
+				return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+			}
+			return c.statuses[resp.StatusCode]
+		}
+	}
+	return &retryingHttpDoer{d: d, cfg: &c}
+}
+
+type retryingHttpDoer struct {
+	d   HttpRequestDoer
+	cfg *retryBuilderImpl
+}
+
+// backoff computes delay before given attempt (starting with 1 for the first retry)
+func (r *retryingHttpDoer) backoff(attempt int) time.Duration {
+	d := r.cfg.initial
+	for i := 1; i < attempt && d < r.cfg.max; i++ {
+		d *= 2
+	}
+	d = min(d, r.cfg.max)
+	if r.cfg.jitter && d > 0 {
+		d = rand.N(d + 1)
+	}
+	return d
+}
+
+// parseRetryAfter parses value of Retry-After header, which is either number of seconds or HTTP date
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+}
+
+func (r *retryingHttpDoer) canRetry(req *http.Request) bool {
+	if !r.cfg.methods[req.Method] {
+		return false
+	}
+	// body can't be sent again unless it can be rewound
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (r *retryingHttpDoer) Do(req *http.Request) (*http.Response, error) {
+	if !r.canRetry(req) {
+		return r.d.Do(req)
+	}
+	ctx := req.Context()
+	var deadline time.Time
+	if r.cfg.maxElapsed > 0 {
+		deadline = time.Now().Add(r.cfg.maxElapsed)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	// first attempt sends request of caller, retries send its clones, so that request is never mutated
+	areq := req
+	for attempt := 1; ; attempt++ {
+		resp, err := r.d.Do(areq)
+		if attempt >= r.cfg.attempts || !r.cfg.policy(areq, resp, err) {
+			return resp, err
+		}
+		wait := r.backoff(attempt)
+		if resp != nil {
+			if ra, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				// server asks for longer delay than we are willing to wait
+				if ra > r.cfg.max {
+					return resp, err
+				}
+				wait = max(wait, ra)
+			}
+		}
+		if !deadline.IsZero() && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+		areq = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return resp, err
+			}
+			areq.Body = body
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		r.cfg.l.DebugContext(ctx, "retrying request", "method", req.Method, "url", req.URL.String(),
+			"attempt", attempt+1, "delay", wait, "error", err)
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// NewRetryBuilder creates RetryBuilder with defaults.
+func NewRetryBuilder() RetryBuilder {
+	return (&retryBuilderImpl{
+		attempts: DefaultRetryAttempts,
+		initial:  DefaultRetryInitialDelay,
+		max:      DefaultRetryMaxDelay,
+		jitter:   true,
+		l:        slog.Default(),
+	}).WithRetryableStatuses(DefaultRetryableStatuses...).WithRetryableMethods(DefaultRetryableMethods...)
+}
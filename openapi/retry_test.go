@@ -0,0 +1,108 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	var (
+		calls   int
+		failFor int
+		bodies  []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if calls <= failFor {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	reset := func(n int) {
+		calls, failFor, bodies = 0, n, nil
+	}
+	d := NewRetryBuilder().WithBackoff(time.Millisecond, 10*time.Millisecond).Build(srv.Client())
+
+	t.Run("recovers", func(t *testing.T) {
+		reset(2)
+		req, _ := http.NewRequest(http.MethodPut, srv.URL, bytes.NewBufferString("payload"))
+		body := req.Body
+		resp, err := d.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+		// request of caller is left intact
+		assert.True(t, req.Body == body)
+	})
+	t.Run("gives up", func(t *testing.T) {
+		reset(5)
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := d.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, DefaultRetryAttempts, calls)
+	})
+	t.Run("non-idempotent method", func(t *testing.T) {
+		reset(1)
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBufferString("payload"))
+		resp, err := d.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("retry after exceeds max delay", func(t *testing.T) {
+		reset(1)
+		d := NewRetryBuilder().WithBackoff(time.Millisecond, time.Millisecond).Build(DoerFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"120"}},
+				Body:       http.NoBody,
+			}, nil
+		}))
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := d.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("5")
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Greater(t, d, 59*time.Minute)
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
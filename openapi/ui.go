@@ -0,0 +1,117 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+
+	DefaultUITitle = "API documentation"
+	DefaultUIPath  = "/docs"
+)
+
+//go:embed ui
+var uiAssets embed.FS
+
+var uiIndexTmpl = template.Must(template.ParseFS(uiAssets, "ui/index.html"))
+
+// UIBuilder is interface to support building of handler that serves interactive API documentation.
+// All assets are embedded into the binary, so no external resources are needed at runtime.
+type UIBuilder interface {
+	// WithTitle sets title of documentation page. By default, DefaultUITitle is used.
+	WithTitle(title string) UIBuilder
+
+	// WithPath sets path where documentation is served. By default, DefaultUIPath is used.
+	WithPath(path string) UIBuilder
+
+	// WithTheme sets color theme, either ThemeLight (default) or ThemeDark.
+	WithTheme(theme string) UIBuilder
+
+	// Build creates http.Handler that serves documentation page and its assets under configured path.
+	Build() http.Handler
+}
+
+type uiBuilderImpl struct {
+	specURL string
+	title   string
+	path    string
+	theme   string
+}
+
+func (u *uiBuilderImpl) WithTitle(title string) UIBuilder {
+	u.title = title
+	return u
+}
+
+func (u *uiBuilderImpl) WithPath(path string) UIBuilder {
+	u.path = path
+	return u
+}
+
+func (u *uiBuilderImpl) WithTheme(theme string) UIBuilder {
+	u.theme = theme
+	return u
+}
+
+func (u *uiBuilderImpl) Build() http.Handler {
+	var index bytes.Buffer
+	if err := uiIndexTmpl.Execute(&index, map[string]string{
+		"Title":   u.title,
+		"SpecURL": u.specURL,
+		"Theme":   u.theme,
+	}); err != nil {
+		// template is embedded and parameters are plain strings, so this is unreachable
+		panic(err)
+	}
+	prefix := strings.TrimSuffix(u.path, "/")
+	assets, _ := fs.Sub(uiAssets, "ui")
+	files := http.StripPrefix(prefix+"/", http.FileServerFS(assets))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case prefix:
+			http.Redirect(w, r, prefix+"/", http.StatusMovedPermanently)
+		case prefix + "/", prefix + "/index.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write(index.Bytes())
+		default:
+			files.ServeHTTP(w, r)
+		}
+	})
+}
+
+// NewUIBuilder creates UIBuilder for documentation of spec available at specURL,
+// which is typically URL served by SpecHandler.
+// Common use is something like:
+//
+//	r.Handle("/docs/", openapi.NewUIBuilder("/spec/openapi.v1.json").Build())
+func NewUIBuilder(specURL string) UIBuilder {
+	return &uiBuilderImpl{
+		specURL: specURL,
+		title:   DefaultUITitle,
+		path:    DefaultUIPath,
+		theme:   ThemeLight,
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Minimal, dependency-free OpenAPI 3 explorer.
+(function () {
+  "use strict";
+
+  var METHODS = ["get", "put", "post", "delete", "options", "head", "patch", "trace"];
+  var app = document.getElementById("app");
+
+  function el(tag, attrs, children) {
+    var e = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === "text") {
+        e.textContent = attrs[k];
+      } else {
+        e.setAttribute(k, attrs[k]);
+      }
+    });
+    (children || []).forEach(function (c) {
+      if (c !== null && c !== undefined) {
+        e.appendChild(typeof c === "string" ? document.createTextNode(c) : c);
+      }
+    });
+    return e;
+  }
+
+  // resolve local reference such as #/components/schemas/Item
+  function resolve(spec, obj) {
+    var seen = 0;
+    while (obj && obj.$ref && seen++ < 32) {
+      if (obj.$ref.indexOf("#/") !== 0) {
+        return obj;
+      }
+      obj = obj.$ref.substring(2).split("/").reduce(function (o, k) {
+        k = k.replace(/~1/g, "/").replace(/~0/g, "~");
+        return o ? o[k] : undefined;
+      }, spec);
+    }
+    return obj;
+  }
+
+  // build example value from schema
+  function example(spec, schema, depth) {
+    schema = resolve(spec, schema) || {};
+    if (depth > 8) {
+      return null;
+    }
+    if (schema.example !== undefined) {
+      return schema.example;
+    }
+    if (schema.default !== undefined) {
+      return schema.default;
+    }
+    if (schema.enum && schema.enum.length) {
+      return schema.enum[0];
+    }
+    var combined = schema.allOf || schema.oneOf || schema.anyOf;
+    if (combined) {
+      var out = {};
+      (schema.allOf ? combined : combined.slice(0, 1)).forEach(function (s) {
+        var v = example(spec, s, depth + 1);
+        if (v && typeof v === "object" && !Array.isArray(v)) {
+          Object.assign(out, v);
+        } else {
+          out = v;
+        }
+      });
+      return out;
+    }
+    switch (schema.type) {
+      case "object":
+        var obj = {};
+        Object.keys(schema.properties || {}).forEach(function (k) {
+          obj[k] = example(spec, schema.properties[k], depth + 1);
+        });
+        return obj;
+      case "array":
+        return [example(spec, schema.items, depth + 1)];
+      case "integer":
+      case "number":
+        return 0;
+      case "boolean":
+        return false;
+      case "string":
+        return schema.format === "date-time" ? new Date(0).toISOString() : "string";
+      default:
+        return schema.properties ? example(spec, Object.assign({type: "object"}, schema), depth) : null;
+    }
+  }
+
+  function schemaView(spec, schema) {
+    var s = resolve(spec, schema);
+    var name = schema && schema.$ref ? schema.$ref.split("/").pop() : null;
+    var box = el("div", {});
+    if (name) {
+      box.appendChild(el("div", {"class": "muted", text: "Schema: " + name}));
+    }
+    box.appendChild(el("pre", {text: JSON.stringify(example(spec, s, 0), null, 2)}));
+    return box;
+  }
+
+  function paramsTable(spec, params) {
+    if (!params.length) {
+      return null;
+    }
+    var rows = params.map(function (p) {
+      var sch = resolve(spec, p.schema) || {};
+      return el("tr", {}, [
+        el("td", {}, [el("code", {text: p.name}), p.required ? el("span", {"class": "error", text: " *"}) : null]),
+        el("td", {text: p.in}),
+        el("td", {text: sch.type || ""}),
+        el("td", {text: p.description || ""})
+      ]);
+    });
+    return el("table", {}, [
+      el("thead", {}, [el("tr", {}, ["Name", "In", "Type", "Description"].map(function (h) {
+        return el("th", {text: h});
+      }))]),
+      el("tbody", {}, rows)
+    ]);
+  }
+
+  function tryIt(spec, baseURL, path, method, params, body) {
+    var form = el("form", {});
+    var inputs = {};
+    params.forEach(function (p) {
+      var input = el("input", {name: p.in + ":" + p.name, placeholder: p.name + " (" + p.in + ")"});
+      inputs[p.in + ":" + p.name] = {param: p, input: input};
+      form.appendChild(el("label", {}, [p.name + " (" + p.in + ")", input]));
+    });
+    var bodyInput = null;
+    var bodyType = null;
+    if (body && body.content) {
+      bodyType = Object.keys(body.content)[0];
+      var sch = body.content[bodyType].schema;
+      bodyInput = el("textarea", {name: "body"});
+      bodyInput.value = bodyType.indexOf("json") >= 0 ? JSON.stringify(example(spec, sch, 0), null, 2) : "";
+      form.appendChild(el("label", {}, ["Request body (" + bodyType + ")", bodyInput]));
+    }
+    var result = el("div", {});
+    form.appendChild(el("button", {type: "submit", text: "Execute"}));
+    form.appendChild(result);
+    form.addEventListener("submit", function (ev) {
+      ev.preventDefault();
+      var url = path;
+      var query = [];
+      var headers = {};
+      Object.keys(inputs).forEach(function (k) {
+        var p = inputs[k].param;
+        var v = inputs[k].input.value;
+        if (v === "") {
+          return;
+        }
+        if (p.in === "path") {
+          url = url.replace("{" + p.name + "}", encodeURIComponent(v));
+        } else if (p.in === "query") {
+          query.push(encodeURIComponent(p.name) + "=" + encodeURIComponent(v));
+        } else if (p.in === "header") {
+          headers[p.name] = v;
+        }
+      });
+      if (query.length) {
+        url += "?" + query.join("&");
+      }
+      var init = {method: method.toUpperCase(), headers: headers};
+      if (bodyInput && bodyInput.value !== "") {
+        headers["Content-Type"] = bodyType;
+        init.body = bodyInput.value;
+      }
+      result.textContent = "";
+      fetch(baseURL + url, init).then(function (resp) {
+        return resp.text().then(function (text) {
+          var hdrs = [];
+          resp.headers.forEach(function (v, k) {
+            hdrs.push(k + ": " + v);
+          });
+          try {
+            text = JSON.stringify(JSON.parse(text), null, 2);
+          } catch (e) {
+            // not a JSON, show as is
+          }
+          result.appendChild(el("p", {text: resp.status + " " + resp.statusText}));
+          result.appendChild(el("pre", {text: hdrs.join("\n")}));
+          result.appendChild(el("pre", {text: text}));
+        });
+      }).catch(function (err) {
+        result.appendChild(el("p", {"class": "error", text: String(err)}));
+      });
+    });
+    return form;
+  }
+
+  function operationView(spec, baseURL, path, method, pathItem, op) {
+    var params = (pathItem.parameters || []).concat(op.parameters || []).map(function (p) {
+      return resolve(spec, p);
+    });
+    var body = resolve(spec, op.requestBody);
+    var content = el("div", {"class": "op-body"}, [
+      op.description ? el("p", {text: op.description}) : null,
+      params.length ? el("h4", {text: "Parameters"}) : null,
+      paramsTable(spec, params)
+    ]);
+    if (body && body.content) {
+      content.appendChild(el("h4", {text: "Request body"}));
+      Object.keys(body.content).forEach(function (ct) {
+        content.appendChild(el("div", {"class": "muted", text: ct}));
+        content.appendChild(schemaView(spec, body.content[ct].schema));
+      });
+    }
+    content.appendChild(el("h4", {text: "Responses"}));
+    Object.keys(op.responses || {}).forEach(function (code) {
+      var r = resolve(spec, op.responses[code]) || {};
+      content.appendChild(el("div", {}, [el("strong", {text: code}), " " + (r.description || "")]));
+      Object.keys(r.content || {}).forEach(function (ct) {
+        content.appendChild(el("div", {"class": "muted", text: ct}));
+        content.appendChild(schemaView(spec, r.content[ct].schema));
+      });
+    });
+    content.appendChild(el("h4", {text: "Try it out"}));
+    content.appendChild(tryIt(spec, baseURL, path, method, params, body));
+    return el("details", {"class": "op", "data-deprecated": String(!!op.deprecated)}, [
+      el("summary", {}, [
+        el("span", {"class": "method " + method, text: method}),
+        el("span", {"class": "path", text: path}),
+        el("span", {"class": "muted", text: op.summary || ""})
+      ]),
+      content
+    ]);
+  }
+
+  function render(spec) {
+    var info = spec.info || {};
+    var baseURL = spec.servers && spec.servers.length ? spec.servers[0].url.replace(/\/$/, "") : "";
+    app.textContent = "";
+    app.appendChild(el("h2", {text: info.title || ""}));
+    app.appendChild(el("p", {}, [el("strong", {text: "Version: "}), info.version || "n/a"]));
+    if (info.description) {
+      app.appendChild(el("p", {text: info.description}));
+    }
+    var groups = {};
+    Object.keys(spec.paths || {}).sort().forEach(function (path) {
+      var item = resolve(spec, spec.paths[path]);
+      METHODS.forEach(function (m) {
+        if (!item[m]) {
+          return;
+        }
+        var tags = item[m].tags && item[m].tags.length ? item[m].tags : ["default"];
+        tags.forEach(function (t) {
+          (groups[t] = groups[t] || []).push(operationView(spec, baseURL, path, m, item, item[m]));
+        });
+      });
+    });
+    Object.keys(groups).sort().forEach(function (t) {
+      app.appendChild(el("h2", {"class": "tag", text: t}));
+      groups[t].forEach(function (v) {
+        app.appendChild(v);
+      });
+    });
+    var schemas = (spec.components || {}).schemas || {};
+    if (Object.keys(schemas).length) {
+      app.appendChild(el("h2", {"class": "tag", text: "Schemas"}));
+      Object.keys(schemas).sort().forEach(function (name) {
+        app.appendChild(el("details", {"class": "op"}, [
+          el("summary", {}, [el("span", {"class": "path", text: name})]),
+          el("div", {"class": "op-body"}, [
+            schemas[name].description ? el("p", {text: schemas[name].description}) : null,
+            el("pre", {text: JSON.stringify(schemas[name], null, 2)})
+          ])
+        ]));
+      });
+    }
+  }
+
+  fetch(app.getAttribute("data-spec-url"), {headers: {"Accept": "application/json"}})
+    .then(function (resp) {
+      if (!resp.ok) {
+        throw new Error("failed to load specification: " + resp.status + " " + resp.statusText);
+      }
+      return resp.json();
+    })
+    .then(render)
+    .catch(function (err) {
+      app.textContent = "";
+      app.appendChild(el("p", {"class": "error", text: String(err)}));
+    });
+})();
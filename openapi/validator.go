@@ -0,0 +1,281 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/rkosegi/go-http-commons/output"
+)
+
+const specProviderKey = "spec"
+
+// LoadSpec loads openapi spec using SpecFileProvider, such as PathToRawSpec generated by oapi-codegen.
+func LoadSpec(prov SpecFileProvider) (*openapi3.T, error) {
+	fn, ok := prov(specProviderKey)[specProviderKey]
+	if !ok || fn == nil {
+		return nil, ErrSpecNotFound
+	}
+	data, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+// ValidatorBuilder is interface to support building of middleware that validates
+// requests (and optionally responses) against openapi spec.
+type ValidatorBuilder interface {
+	// WithOutput sets output.Interface used to report violations.
+	// By default, output.DefaultOutput is used.
+	WithOutput(out output.Interface) ValidatorBuilder
+
+	// WithLogger sets slog.Logger used to log violations. By default, slog.Default() is used.
+	WithLogger(l *slog.Logger) ValidatorBuilder
+
+	// WithOptions sets options passed to openapi3filter.
+	// By default, authentication is not validated and all errors are reported at once.
+	WithOptions(opts *openapi3filter.Options) ValidatorBuilder
+
+	// WithResponseValidation enables validation of responses.
+	// Response is buffered until it is validated, so this is not suitable for streaming.
+	WithResponseValidation() ValidatorBuilder
+
+	// RejectUnknownRoutes causes requests that don't match any operation in spec to be rejected
+	// with 404 or 405. By default, such requests are passed through without validation.
+	// Note that when servers of spec contain host name, request must be sent to that host to match,
+	// so requests sent through any other host name are considered unknown.
+	RejectUnknownRoutes() ValidatorBuilder
+
+	// ReportOnly causes violations to be logged only, requests and responses are passed through unchanged.
+	ReportOnly() ValidatorBuilder
+
+	// Build returns MiddlewareFunc with all configured aspects.
+	Build() func(http.Handler) http.Handler
+}
+
+type validatorBuilderImpl struct {
+	router        routers.Router
+	hostServers   []string
+	out           output.Interface
+	l             *slog.Logger
+	opts          *openapi3filter.Options
+	validateResp  bool
+	rejectUnknown bool
+	reportOnly    bool
+}
+
+func (v *validatorBuilderImpl) WithOutput(out output.Interface) ValidatorBuilder {
+	v.out = out
+	return v
+}
+
+func (v *validatorBuilderImpl) WithLogger(l *slog.Logger) ValidatorBuilder {
+	v.l = l
+	return v
+}
+
+func (v *validatorBuilderImpl) WithOptions(opts *openapi3filter.Options) ValidatorBuilder {
+	v.opts = opts
+	return v
+}
+
+func (v *validatorBuilderImpl) WithResponseValidation() ValidatorBuilder {
+	v.validateResp = true
+	return v
+}
+
+func (v *validatorBuilderImpl) RejectUnknownRoutes() ValidatorBuilder {
+	v.rejectUnknown = true
+	return v
+}
+
+func (v *validatorBuilderImpl) ReportOnly() ValidatorBuilder {
+	v.reportOnly = true
+	return v
+}
+
+// violation creates Problem that describes validation error
+func violation(title string, status int, err error) *output.Problem {
+	p := &output.Problem{
+		Title:  title,
+		Status: status,
+		Detail: err.Error(),
+	}
+	var me openapi3.MultiError
+	if errors.As(err, &me) {
+		details := make([]string, 0, len(me))
+		for _, e := range me {
+			details = append(details, e.Error())
+		}
+		p.Detail = title
+		p.Extensions = map[string]interface{}{"errors": details}
+	}
+	return p
+}
+
+// serversWithHost returns URLs of servers that contain host name
+func serversWithHost(spec *openapi3.T) []string {
+	var res []string
+	for _, s := range spec.Servers {
+		if u, err := url.Parse(s.URL); err == nil && len(u.Host) > 0 {
+			res = append(res, s.URL)
+		}
+	}
+	return res
+}
+
+func (v *validatorBuilderImpl) Build() func(http.Handler) http.Handler {
+	c := *v
+	if len(c.hostServers) > 0 && !c.rejectUnknown {
+		c.l.Warn("openapi spec servers contain host name, requests sent to other hosts are not validated",
+			"servers", c.hostServers)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := c.router.FindRoute(r)
+			if err != nil {
+				if !c.rejectUnknown || c.reportOnly {
+					c.l.DebugContext(r.Context(), "request does not match any operation in openapi spec, skipping validation",
+						"method", r.Method, "host", r.Host, "path", r.URL.Path, "error", err)
+					next.ServeHTTP(w, r)
+					return
+				}
+				status := http.StatusNotFound
+				if errors.Is(err, routers.ErrMethodNotAllowed) {
+					status = http.StatusMethodNotAllowed
+				}
+				c.out.SendWithStatus(w, output.WrapError(err, status), status)
+				return
+			}
+			reqInput := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    c.opts,
+			}
+			if err = openapi3filter.ValidateRequest(r.Context(), reqInput); err != nil {
+				c.l.WarnContext(r.Context(), "request does not conform to openapi spec",
+					"method", r.Method, "path", r.URL.Path, "error", err)
+				if !c.reportOnly {
+					c.out.SendWithStatus(w, violation("Request validation failed", http.StatusBadRequest, err), http.StatusBadRequest)
+					return
+				}
+			}
+			if !c.validateResp {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec := &bufferedResponse{header: http.Header{}}
+			next.ServeHTTP(rec, r)
+			if err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: reqInput,
+				Status:                 rec.statusCode(),
+				Header:                 rec.header,
+				Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+				Options:                c.opts,
+			}); err != nil {
+				c.l.ErrorContext(r.Context(), "response does not conform to openapi spec",
+					"method", r.Method, "path", r.URL.Path, "status", rec.statusCode(), "error", err)
+				if !c.reportOnly {
+					c.out.SendWithStatus(w, violation("Response validation failed", http.StatusInternalServerError, err), http.StatusInternalServerError)
+					return
+				}
+			}
+			rec.flushTo(w)
+		})
+	}
+}
+
+// bufferedResponse holds response in memory until it is validated
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(data)
+}
+
+func (b *bufferedResponse) WriteHeader(statusCode int) {
+	if b.status == 0 {
+		b.status = statusCode
+	}
+}
+
+func (b *bufferedResponse) statusCode() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+func (b *bufferedResponse) flushTo(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(b.statusCode())
+	_, _ = w.Write(b.body.Bytes())
+}
+
+// NewValidatorBuilder creates ValidatorBuilder for given openapi spec.
+// Error is returned if routes can't be created from spec.
+// Routes are matched against servers of spec, including host name if present. Requests that don't match
+// any route are not validated unless RejectUnknownRoutes is used, so spec that is served through
+// different host name than in its servers should list only relative server URLs (such as "/api/v1").
+func NewValidatorBuilder(spec *openapi3.T) (ValidatorBuilder, error) {
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &validatorBuilderImpl{
+		router:      router,
+		hostServers: serversWithHost(spec),
+		out:         out,
+		l:           slog.Default(),
+		opts: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// NewValidatorBuilderFromProvider creates ValidatorBuilder for openapi spec obtained from SpecFileProvider.
+func NewValidatorBuilderFromProvider(prov SpecFileProvider) (ValidatorBuilder, error) {
+	spec, err := LoadSpec(prov)
+	if err != nil {
+		return nil, err
+	}
+	return NewValidatorBuilder(spec)
+}
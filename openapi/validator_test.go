@@ -0,0 +1,148 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `
+openapi: 3.0.0
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /items/{id}:
+    put:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+`
+
+func TestValidator(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	assert.NoError(t, err)
+
+	respBody := `{"id":1}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(respBody))
+	})
+	newReq := func(path, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	vb, err := NewValidatorBuilder(spec)
+	assert.NoError(t, err)
+	h := vb.WithResponseValidation().Build()(handler)
+
+	t.Run("valid", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/items/1", `{"name":"x"}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, respBody, rec.Body.String())
+	})
+	t.Run("invalid path param", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/items/abc", `{"name":"x"}`))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("invalid body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/items/1", `{}`))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("unknown route passes", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/other", `{}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("invalid response", func(t *testing.T) {
+		respBody = `{"id":"x"}`
+		defer func() { respBody = `{"id":1}` }()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/items/1", `{"name":"x"}`))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+	t.Run("report only", func(t *testing.T) {
+		vb, err := NewValidatorBuilder(spec)
+		assert.NoError(t, err)
+		h := vb.ReportOnly().RejectUnknownRoutes().Build()(handler)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/items/1", `{}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("reject unknown", func(t *testing.T) {
+		vb, err := NewValidatorBuilder(spec)
+		assert.NoError(t, err)
+		h := vb.RejectUnknownRoutes().Build()(handler)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq("/other", `{}`))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestLoadSpecMissing(t *testing.T) {
+	_, err := LoadSpec(func(string) map[string]func() ([]byte, error) {
+		return map[string]func() ([]byte, error){}
+	})
+	assert.ErrorIs(t, err, ErrSpecNotFound)
+}
+
+func TestValidatorServersWithHost(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	assert.NoError(t, err)
+	spec.Servers = openapi3.Servers{{URL: "https://api.example.com/v1"}}
+	var logs bytes.Buffer
+	vb, err := NewValidatorBuilder(spec)
+	assert.NoError(t, err)
+	vb.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))).Build()
+	assert.Contains(t, logs.String(), "requests sent to other hosts are not validated")
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+var ErrNotAcceptable = errors.New("none of the available representations is acceptable")
+
+type mediaRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// specificity returns how specific is this range, or -1 if it does not match given media type
+func (m mediaRange) specificity(typ, subtype string) int {
+	switch {
+	case m.typ == "*" && m.subtype == "*":
+		return 0
+	case m.typ == typ && m.subtype == "*":
+		return 1
+	case m.typ == typ && m.subtype == subtype:
+		return 2
+	default:
+		return -1
+	}
+}
+
+func parseAccept(accept string) []mediaRange {
+	var out []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		typ, subtype, _ := strings.Cut(mt, "/")
+		if len(subtype) == 0 {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		out = append(out, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+	return out
+}
+
+func baseMediaType(ct string) string {
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	return strings.ToLower(strings.TrimSpace(ct))
+}
+
+// NegotiateContentType selects one of offered content types according to value of Accept header.
+// Offers are expected to be ordered by preference, first one wins when quality values are equal.
+// If accept is empty, first offer is returned. Empty string is returned when none of offers is acceptable.
+func NegotiateContentType(accept string, offers []string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if len(strings.TrimSpace(accept)) == 0 {
+		return offers[0]
+	}
+	ranges := parseAccept(accept)
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, offer := range offers {
+		typ, subtype, _ := strings.Cut(baseMediaType(offer), "/")
+		// quality of offer is determined by the most specific matching range
+		q, spec := 0.0, -1
+		for _, r := range ranges {
+			if s := r.specificity(typ, subtype); s > spec {
+				q, spec = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	offers := []string{"application/json", "application/yaml", "text/csv"}
+	for _, tc := range []struct {
+		accept string
+		exp    string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/yaml", "application/yaml"},
+		{"text/*", "text/csv"},
+		{"application/json;q=0.5, application/yaml", "application/yaml"},
+		{"application/*;q=0.2, application/json;q=0", "application/yaml"},
+		{"text/csv;q=0.9, */*;q=0.1", "text/csv"},
+		{"image/png", ""},
+	} {
+		assert.Equal(t, tc.exp, NegotiateContentType(tc.accept, offers), tc.accept)
+	}
+}
+
+func TestSendNegotiated(t *testing.T) {
+	o := NewBuilder().AddEncoder("text/plain", func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprint(w, v)
+		return err
+	}).Build()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	SendNegotiated(o, rec, req, "hello", http.StatusOK)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "hello", rec.Body.String())
+
+	req.Header.Set("Accept", "application/xml")
+	rec = httptest.NewRecorder()
+	SendNegotiated(o, rec, req, "hello", http.StatusOK)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+
+	// Interface without negotiation support
+	rec = httptest.NewRecorder()
+	SendNegotiated(plainOutput{o}, rec, req, "hello", http.StatusOK)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type plainOutput struct {
+	Interface
+}
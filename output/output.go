@@ -44,12 +44,36 @@ type Interface interface {
 	SendBytes(w http.ResponseWriter, data []byte)
 }
 
+// Negotiator is optionally implemented by Interface that supports content negotiation.
+// Interface created by Builder implements it.
+type Negotiator interface {
+	// SendNegotiated is like SendWithStatus, but it selects PayloadEncoder based on Accept header of request.
+	// If none of registered encoders is acceptable, 406 Not Acceptable is sent instead.
+	SendNegotiated(w http.ResponseWriter, r *http.Request, v interface{}, status int)
+}
+
+// SendNegotiated sends object using content negotiation if out implements Negotiator,
+// otherwise it falls back to SendWithStatus.
+func SendNegotiated(out Interface, w http.ResponseWriter, r *http.Request, v interface{}, status int) {
+	if n, ok := out.(Negotiator); ok {
+		n.SendNegotiated(w, r, v, status)
+		return
+	}
+	out.SendWithStatus(w, v, status)
+}
+
 type Builder interface {
 	// WithEncoder sets default content-type and PayloadEncoder that serializes
 	// objects into io.Writer in that content-type.
 	WithEncoder(ct string, encFunc PayloadEncoder) Builder
+	// AddEncoder registers additional content-type and PayloadEncoder that is considered
+	// during content negotiation. Encoders are preferred in order they were added, after default one.
+	AddEncoder(ct string, encFunc PayloadEncoder) Builder
 	// WithErrorMapper registers mapping function that can customize error response
 	WithErrorMapper(mapper ErrorMapper) Builder
+	// WithProblemDetails causes errors that are not handled by any ErrorMapper
+	// to be sent as RFC 9457 Problem Details document instead of plain text.
+	WithProblemDetails() Builder
 	// Build creates new Interface using current state of this Builder.
 	Build() Interface
 }
@@ -62,10 +86,17 @@ func defaultJsonEncoder() PayloadEncoder {
 	}
 }
 
-type builder struct {
-	encFn PayloadEncoder
+type encoderEntry struct {
 	ct    string
-	ems   []ErrorMapper
+	encFn PayloadEncoder
+}
+
+type builder struct {
+	encFn    PayloadEncoder
+	ct       string
+	extra    []encoderEntry
+	ems      []ErrorMapper
+	problems bool
 }
 
 func (b *builder) WithErrorMapper(mapper ErrorMapper) Builder {
@@ -73,8 +104,14 @@ func (b *builder) WithErrorMapper(mapper ErrorMapper) Builder {
 	return b
 }
 
+func (b *builder) WithProblemDetails() Builder {
+	b.problems = true
+	return b
+}
+
 type impl struct {
 	*builder
+	offers []string
 }
 
 func (i *impl) SendBytes(w http.ResponseWriter, data []byte) {
@@ -85,32 +122,86 @@ func (i *impl) SendBytes(w http.ResponseWriter, data []byte) {
 func (i *impl) SendWithStatus(w http.ResponseWriter, v interface{}, status int) {
 	// check if value to send is error
 	if err, ok := v.(error); ok {
-		// if so, consult the list of ErrorMappers, if one of them can handle that
-		for _, em := range i.ems {
-			if em(w, err) {
-				return
-			}
+		i.sendError(w, err, status)
+		return
+	}
+	i.send(w, i.ct, i.encFn, v, status)
+}
+
+func (i *impl) SendNegotiated(w http.ResponseWriter, r *http.Request, v interface{}, status int) {
+	if err, ok := v.(error); ok {
+		i.sendError(w, err, status)
+		return
+	}
+	w.Header().Add("Vary", "Accept")
+	ct := NegotiateContentType(r.Header.Get("Accept"), i.offers)
+	if len(ct) == 0 {
+		i.sendError(w, WrapError(ErrNotAcceptable, http.StatusNotAcceptable), http.StatusNotAcceptable)
+		return
+	}
+	i.send(w, ct, i.encoderFor(ct), v, status)
+}
+
+func (i *impl) encoderFor(ct string) PayloadEncoder {
+	for _, e := range i.extra {
+		if e.ct == ct {
+			return e.encFn
 		}
-		// otherwise use http.Error
-		http.Error(w, err.Error(), status)
+	}
+	return i.encFn
+}
+
+func (i *impl) sendError(w http.ResponseWriter, err error, status int) {
+	// consult the list of ErrorMappers, if one of them can handle that
+	for _, em := range i.ems {
+		if em(w, err) {
+			return
+		}
+	}
+	status = statusOf(err, status)
+	if i.problems {
+		i.sendProblem(w, ProblemFromError(err, status))
 		return
 	}
-	w.Header().Set("Content-Type", i.ct)
+	// otherwise use http.Error
+	http.Error(w, err.Error(), status)
+}
+
+func (i *impl) send(w http.ResponseWriter, ct string, encFn PayloadEncoder, v interface{}, status int) {
+	w.Header().Set("Content-Type", ct)
 	w.WriteHeader(status)
 	// encode value using configured PayloadEncoder
-	if err := i.encFn(w, v); err != nil {
+	if err := encFn(w, v); err != nil {
 		// last resort to send back something
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+func (i *impl) sendProblem(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
 func (b *builder) Build() Interface {
+	offers := []string{b.ct}
+	var extra []encoderEntry
+	for _, e := range b.extra {
+		if e.ct != b.ct {
+			offers = append(offers, e.ct)
+			extra = append(extra, e)
+		}
+	}
 	return &impl{
 		builder: &builder{
-			encFn: b.encFn,
-			ct:    b.ct,
-			ems:   b.ems,
+			encFn:    b.encFn,
+			ct:       b.ct,
+			extra:    extra,
+			ems:      b.ems,
+			problems: b.problems,
 		},
+		offers: offers,
 	}
 }
 
@@ -120,6 +211,11 @@ func (b *builder) WithEncoder(ct string, encFunc PayloadEncoder) Builder {
 	return b
 }
 
+func (b *builder) AddEncoder(ct string, encFunc PayloadEncoder) Builder {
+	b.extra = append(b.extra, encoderEntry{ct: ct, encFn: encFunc})
+	return b
+}
+
 func NewBuilder() Builder {
 	return &builder{
 		encFn: defaultJsonEncoder(),
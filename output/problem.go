@@ -0,0 +1,146 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const (
+	// ProblemContentType is media type of RFC 9457 Problem Details JSON document
+	ProblemContentType = "application/problem+json"
+
+	// DefaultProblemType is problem type URI used when none is specified
+	DefaultProblemType = "about:blank"
+)
+
+// Problem is RFC 9457 Problem Details object.
+// Problem implements error, so it can be sent directly using Interface.SendWithStatus.
+type Problem struct {
+	// Type is URI reference that identifies the problem type
+	Type string `json:"type,omitempty"`
+	// Title is short, human-readable summary of the problem type
+	Title string `json:"title,omitempty"`
+	// Status is HTTP status code
+	Status int `json:"status,omitempty"`
+	// Detail is human-readable explanation specific to this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance is URI reference that identifies the specific occurrence of the problem
+	Instance string `json:"instance,omitempty"`
+	// Extensions are additional members of problem object, they are serialized at top level
+	Extensions map[string]interface{} `json:"-"`
+}
+
+func (p *Problem) Error() string {
+	if len(p.Detail) == 0 {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
+
+// MarshalJSON serializes Problem, including extension members
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	typ := p.Type
+	if len(typ) == 0 {
+		typ = DefaultProblemType
+	}
+	m["type"] = typ
+	if len(p.Title) > 0 {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if len(p.Detail) > 0 {
+		m["detail"] = p.Detail
+	}
+	if len(p.Instance) > 0 {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// StatusError is error that carries HTTP status code and optional problem type URI.
+type StatusError struct {
+	// Err is wrapped error
+	Err error
+	// Status is HTTP status code
+	Status int
+	// Type is problem type URI
+	Type string
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// WrapError wraps err so that it is sent with given status code.
+func WrapError(err error, status int) error {
+	return &StatusError{Err: err, Status: status}
+}
+
+// WrapErrorWithType wraps err so that it is sent with given status code and problem type URI.
+func WrapErrorWithType(err error, status int, typeURI string) error {
+	return &StatusError{Err: err, Status: status, Type: typeURI}
+}
+
+// statusOf returns status code carried by err, or def if there is none.
+func statusOf(err error, def int) int {
+	var se *StatusError
+	if errors.As(err, &se) && se.Status != 0 {
+		return se.Status
+	}
+	var p *Problem
+	if errors.As(err, &p) && p.Status != 0 {
+		return p.Status
+	}
+	return def
+}
+
+// ProblemFromError converts error into Problem.
+// If err is (or wraps) Problem, copy of it is returned.
+// Status code and type URI are taken from StatusError found in chain, if any, otherwise status is used.
+func ProblemFromError(err error, status int) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		out := *p
+		if out.Status == 0 {
+			out.Status = status
+		}
+		return &out
+	}
+	out := &Problem{
+		Status: statusOf(err, status),
+		Detail: err.Error(),
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		out.Type = se.Type
+	}
+	out.Title = http.StatusText(out.Status)
+	return out
+}
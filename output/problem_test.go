@@ -0,0 +1,65 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemDetails(t *testing.T) {
+	o := NewBuilder().WithProblemDetails().Build()
+
+	t.Run("plain error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		o.SendWithStatus(rec, errors.New("boom"), http.StatusInternalServerError)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"boom"}`, rec.Body.String())
+	})
+	t.Run("wrapped status error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := fmt.Errorf("lookup: %w", WrapErrorWithType(errors.New("no such item"), http.StatusNotFound, "https://example.com/not-found"))
+		o.SendWithStatus(rec, err, http.StatusInternalServerError)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"type":"https://example.com/not-found","title":"Not Found","status":404,"detail":"lookup: no such item"}`, rec.Body.String())
+	})
+	t.Run("problem with extensions", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		o.SendWithStatus(rec, &Problem{
+			Title:      "Out of credit",
+			Status:     http.StatusForbidden,
+			Extensions: map[string]interface{}{"balance": 30},
+		}, http.StatusInternalServerError)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Out of credit","status":403,"balance":30}`, rec.Body.String())
+	})
+	t.Run("mapper takes precedence", func(t *testing.T) {
+		o := NewBuilder().WithProblemDetails().WithErrorMapper(func(w http.ResponseWriter, err error) bool {
+			w.WriteHeader(http.StatusTeapot)
+			return true
+		}).Build()
+		rec := httptest.NewRecorder()
+		o.SendWithStatus(rec, errors.New("boom"), http.StatusInternalServerError)
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+	})
+}
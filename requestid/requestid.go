@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requestid carries request (correlation) ID through context, logs and outgoing calls.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHeader is name of HTTP header that carries request ID
+	DefaultHeader = "X-Request-ID"
+
+	// LogKey is attribute key used when request ID is added to log record
+	LogKey = "request_id"
+
+	// MaxLength is maximum length of request ID that is accepted from client
+	MaxLength = 128
+
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base32hex = "0123456789abcdefghijklmnopqrstuv"
+)
+
+// Generator generates new request ID.
+type Generator func() string
+
+func formatUUID(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// UUIDv4 generates random UUID (RFC 9562, version 4).
+func UUIDv4() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+// UUIDv7 generates time-ordered UUID (RFC 9562, version 7).
+func UUIDv7() string {
+	var b [16]byte
+	_, _ = rand.Read(b[6:])
+	putMillis(b[:], time.Now())
+	b[6] = (b[6] & 0x0f) | 0x70
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+// ULID generates Universally Unique Lexicographically Sortable Identifier.
+func ULID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[6:])
+	putMillis(b[:], time.Now())
+	s := new(big.Int).SetBytes(b[:]).Text(32)
+	var sb strings.Builder
+	sb.Grow(26)
+	for range 26 - len(s) {
+		sb.WriteByte('0')
+	}
+	for _, c := range []byte(s) {
+		sb.WriteByte(crockford[strings.IndexByte(base32hex, c)])
+	}
+	return sb.String()
+}
+
+// putMillis writes 48-bit unix timestamp in milliseconds into first 6 bytes of b
+func putMillis(b []byte, t time.Time) {
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(t.UnixMilli()))
+	copy(b[:6], ts[2:])
+}
+
+// IsValid checks whether request ID received from client is safe to use,
+// that is, it is not empty, not too long and consists of printable ASCII characters only.
+func IsValid(id string) bool {
+	if len(id) == 0 || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+type ctxKey struct{}
+
+// NewContext returns copy of parent context that carries provided request ID.
+func NewContext(parent context.Context, id string) context.Context {
+	return context.WithValue(parent, ctxKey{}, id)
+}
+
+// FromContext returns request ID carried by ctx, or empty string if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); len(id) > 0 {
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// NewLogHandler wraps slog.Handler so that request ID carried by context is added
+// to every record logged using one of *Context methods of slog.Logger.
+func NewLogHandler(h slog.Handler) slog.Handler {
+	return &logHandler{Handler: h}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerators(t *testing.T) {
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), UUIDv4())
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), UUIDv7())
+	assert.Regexp(t, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), ULID())
+	assert.NotEqual(t, UUIDv4(), UUIDv4())
+	assert.NotEqual(t, ULID(), ULID())
+}
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, IsValid("abc-123"))
+	assert.False(t, IsValid(""))
+	assert.False(t, IsValid("abc 123"))
+	assert.False(t, IsValid("abc\n123"))
+	assert.False(t, IsValid(string(make([]byte, MaxLength+1))))
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil))).With("app", "x")
+	l.InfoContext(NewContext(context.Background(), "id-1"), "hello")
+	assert.Contains(t, buf.String(), "app=x request_id=id-1")
+	buf.Reset()
+	l.InfoContext(context.Background(), "hello")
+	assert.NotContains(t, buf.String(), LogKey)
+}
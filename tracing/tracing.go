@@ -0,0 +1,172 @@
+/*
+Copyright 2026 Richard Kosegi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing implements propagation of W3C Trace Context (traceparent and tracestate headers).
+// It does not record nor export spans, it only carries identifiers so that logs and outgoing calls
+// can be correlated with the incoming request.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+
+	// FlagSampled is trace flag that indicates that caller may have recorded trace data
+	FlagSampled byte = 0x01
+)
+
+var (
+	ErrInvalidTraceParent = errors.New("invalid traceparent header")
+
+	zeroTraceID [16]byte
+	zeroSpanID  [8]byte
+)
+
+// SpanContext identifies position of current operation within trace.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+	// State is opaque value of tracestate header, passed as-is.
+	State string
+}
+
+// IsValid returns true if neither of identifiers is all zeroes.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != zeroTraceID && sc.SpanID != zeroSpanID
+}
+
+// TraceIDString returns hex representation of trace ID.
+func (sc SpanContext) TraceIDString() string {
+	return hex.EncodeToString(sc.TraceID[:])
+}
+
+// SpanIDString returns hex representation of span ID.
+func (sc SpanContext) SpanIDString() string {
+	return hex.EncodeToString(sc.SpanID[:])
+}
+
+// TraceParent formats SpanContext as value of traceparent header.
+func (sc SpanContext) TraceParent() string {
+	return "00-" + sc.TraceIDString() + "-" + sc.SpanIDString() + "-" + hex.EncodeToString([]byte{sc.Flags})
+}
+
+// Child returns SpanContext within same trace, with newly generated span ID.
+func (sc SpanContext) Child() SpanContext {
+	c := sc
+	c.SpanID = newSpanID()
+	return c
+}
+
+// New creates SpanContext of new sampled trace.
+func New() SpanContext {
+	sc := SpanContext{Flags: FlagSampled, SpanID: newSpanID()}
+	for sc.TraceID == zeroTraceID {
+		_, _ = rand.Read(sc.TraceID[:])
+	}
+	return sc
+}
+
+func newSpanID() (id [8]byte) {
+	for id == zeroSpanID {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != 2*len(dst) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// Parse parses values of traceparent and tracestate headers.
+func Parse(traceParent, traceState string) (SpanContext, error) {
+	var (
+		sc      SpanContext
+		version [1]byte
+		flags   [1]byte
+	)
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || !decodeHex(version[:], parts[0]) || version[0] == 0xff {
+		return sc, ErrInvalidTraceParent
+	}
+	// future versions may append fields, version 00 must have exactly 4
+	if version[0] == 0 && len(parts) != 4 {
+		return sc, ErrInvalidTraceParent
+	}
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) ||
+		!decodeHex(flags[:], parts[3]) || !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	sc.Flags = flags[0]
+	sc.State = strings.TrimSpace(traceState)
+	return sc, nil
+}
+
+// Extract parses span context from headers.
+func Extract(h http.Header) (SpanContext, error) {
+	return Parse(h.Get(TraceParentHeader), strings.Join(h.Values(TraceStateHeader), ","))
+}
+
+// Inject sets headers to propagate given span context.
+func Inject(h http.Header, sc SpanContext) {
+	h.Set(TraceParentHeader, sc.TraceParent())
+	if len(sc.State) > 0 {
+		h.Set(TraceStateHeader, sc.State)
+	} else {
+		h.Del(TraceStateHeader)
+	}
+}
+
+type ctxKey struct{}
+
+// NewContext returns copy of parent context that carries provided span context.
+func NewContext(parent context.Context, sc SpanContext) context.Context {
+	return context.WithValue(parent, ctxKey{}, sc)
+}
+
+// FromContext returns span context carried by ctx, if any.
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(ctxKey{}).(SpanContext)
+	return sc, ok
+}
+
+// Middleware extracts span context from incoming request headers, or starts new trace if there is none,
+// and makes it available to handlers through request context.
+// Current span is echoed back to caller in traceparent response header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc, err := Extract(r.Header)
+		if err != nil {
+			sc = New()
+		} else {
+			sc = sc.Child()
+		}
+		w.Header().Set(TraceParentHeader, sc.TraceParent())
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), sc)))
+	})
+}